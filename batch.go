@@ -0,0 +1,181 @@
+package mbuckets
+
+import (
+	"fmt"
+)
+
+// Kinds of the operations queued in a Batch
+const (
+	batchPut = iota
+	batchDelete
+	batchCreateBucket
+	batchDeleteBucket
+)
+
+type batchOp struct {
+	kind   int
+	bucket *Bucket
+	key    []byte
+	value  []byte
+
+	// The innermost Savepoint the operation was queued in, nil if none
+	savepoint *Savepoint
+}
+
+// Batch queues write operations on the Buckets of a DB, and applies all of them in a single transaction on Commit.
+// A Batch is not safe for concurrent use.
+type Batch struct {
+	db  *DB
+	ops []batchOp
+
+	// The Savepoint whose function is queueing operations, nil if none
+	current *Savepoint
+}
+
+// Savepoint is a group of operations queued in a Batch by Batch.Savepoint, which is rolled back on its own if one of them fails
+type Savepoint struct {
+	// The error that rolled back the operations of this Savepoint, nil if they were not rolled back
+	Err error
+
+	parent *Savepoint
+}
+
+// within reports whether this Savepoint is Savepoint `sp` or nested in it
+func (savepoint *Savepoint) within(sp *Savepoint) bool {
+	for ; savepoint != nil; savepoint = savepoint.parent {
+		if savepoint == sp {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewBatch returns an empty Batch of operations on the Buckets of this DB
+func (db *DB) NewBatch() *Batch {
+	return &Batch{db: db}
+}
+
+// Put queues putting the given key/value pair in the bolt.Bucket specified by Bucket `b`, see Bucket.Insert.
+// The key and value are copied.
+func (batch *Batch) Put(b *Bucket, key, value []byte) {
+	batch.ops = append(batch.ops, batchOp{batchPut, b, copyKey(key), copyKey(value), batch.current})
+}
+
+// Delete queues removing the given key from the bolt.Bucket specified by Bucket `b`, see Bucket.Delete.
+// The key is copied.
+func (batch *Batch) Delete(b *Bucket, key []byte) {
+	batch.ops = append(batch.ops, batchOp{batchDelete, b, copyKey(key), nil, batch.current})
+}
+
+// CreateBucket queues creating the bolt.Bucket specified by Bucket `b`, see Bucket.CreateBucket
+func (batch *Batch) CreateBucket(b *Bucket) {
+	batch.ops = append(batch.ops, batchOp{batchCreateBucket, b, nil, nil, batch.current})
+}
+
+// DeleteBucket queues deleting the bolt.Bucket specified by Bucket `b`, see Bucket.DeleteBucket
+func (batch *Batch) DeleteBucket(b *Bucket) {
+	batch.ops = append(batch.ops, batchOp{batchDeleteBucket, b, nil, nil, batch.current})
+}
+
+// Savepoint queues the operations queued by function `fn` as a group, which can be nested in other Savepoints.
+//
+// If function `fn` returns an error, the operations it queued are dropped right away. If one of them fails on Commit,
+// the transaction is rolled back, the operations of the innermost Savepoint holding the failed operation are dropped,
+// and the remaining operations are applied again in a new transaction. In both cases the error is set as Err
+// of the returned Savepoint, so that an optional step can fail without aborting the whole Batch.
+func (batch *Batch) Savepoint(fn func(*Batch) error) *Savepoint {
+	savepoint := &Savepoint{parent: batch.current}
+	start := len(batch.ops)
+
+	batch.current = savepoint
+	err := fn(batch)
+	batch.current = savepoint.parent
+
+	if err != nil {
+		batch.ops = batch.ops[:start]
+		savepoint.Err = err
+	}
+
+	return savepoint
+}
+
+// rollback drops the operations queued in Savepoint `savepoint` and in all Savepoints nested in it
+func (batch *Batch) rollback(savepoint *Savepoint) {
+	ops := batch.ops[:0]
+	for _, op := range batch.ops {
+		if !op.savepoint.within(savepoint) {
+			ops = append(ops, op)
+		}
+	}
+
+	batch.ops = ops
+}
+
+// Len returns the number of queued operations
+func (batch *Batch) Len() int {
+	return len(batch.ops)
+}
+
+// Reset drops all queued operations
+func (batch *Batch) Reset() {
+	batch.ops = nil
+}
+
+// Commit applies the queued operations in order in a single transaction, and drops them if the transaction commits,
+// even if a hook then fails with a *HookError.
+// If an operation outside any Savepoint fails, none of them is applied and its error is returned.
+// Failed operations in a Savepoint roll back their Savepoint instead, see Batch.Savepoint.
+func (batch *Batch) Commit() error {
+	for _, op := range batch.ops {
+		if op.bucket.DB != batch.db {
+			return fmt.Errorf("Bucket %s belongs to a different DB", op.bucket.Name)
+		}
+	}
+
+	for {
+		var failed *Savepoint
+
+		err := batch.db.UpdateTx(func(tx *Tx) error {
+			failed = nil
+
+			for _, op := range batch.ops {
+				err := op.apply(tx.Bind(op.bucket))
+				if err != nil {
+					failed = op.savepoint
+					return err
+				}
+			}
+			return nil
+		})
+
+		if err != nil && failed != nil {
+			failed.Err = err
+			batch.rollback(failed)
+			continue
+		}
+
+		if err != nil && !isHookError(err) {
+			return err
+		}
+
+		batch.ops = nil
+		return err
+	}
+}
+
+// apply performs this operation on Bucket `b`
+func (op batchOp) apply(b *Bucket) error {
+	switch op.kind {
+	case batchPut:
+		return b.Insert(op.key, op.value)
+	case batchDelete:
+		return b.Delete(op.key)
+	case batchCreateBucket:
+		return b.CreateBucket()
+	case batchDeleteBucket:
+		return b.DeleteBucket()
+	}
+
+	return fmt.Errorf("Unknown batch operation: %d", op.kind)
+}
@@ -0,0 +1,173 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestBatch(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Inserting key/value pairs in bucket: Data")
+	err = db.BucketString("Data").InsertAllString(map[string]string{"stale": "value", "key1": "value1"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Creating bucket: Obsolete")
+	err = db.BucketString("Obsolete").CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	batch := db.NewBatch()
+	batch.Put(db.BucketString("Data"), []byte("key2"), []byte("value2"))
+	batch.Delete(db.BucketString("Data"), []byte("stale"))
+	batch.CreateBucket(db.BucketString("Index/Empty"))
+	batch.Put(db.BucketString("Index/Value"), []byte("value2"), []byte("key2"))
+	batch.DeleteBucket(db.BucketString("Obsolete"))
+
+	t.Logf("Committing batch of %d operations", batch.Len())
+	err = batch.Commit()
+	if err != nil {
+		t.Errorf("Unable to commit batch. Error: %s", err.Error())
+	}
+
+	if batch.Len() != 0 {
+		t.Errorf("Batch still holds %d operations after commit", batch.Len())
+	}
+
+	keys, err := db.BucketString("Data").GetAllKeys()
+	if err != nil {
+		t.Errorf("Unable to retrieve keys. Error: %s", err.Error())
+	}
+
+	if len(keys) != 2 || string(keys[0]) != "key1" || string(keys[1]) != "key2" {
+		t.Errorf("Keys %q do not match the committed batch", keys)
+	}
+
+	for name, expected := range map[string]bool{"Index/Empty": true, "Index/Value": true, "Obsolete": false} {
+		exists, err := db.BucketString(name).BucketExists()
+		if err != nil {
+			t.Errorf("Unable to check bucket. Error: %s", err.Error())
+		}
+
+		if exists != expected {
+			t.Errorf("Existence %t of bucket %s does not match the committed batch", exists, name)
+		}
+	}
+
+	t.Log("Committing batch with a failing operation")
+	batch.Put(db.BucketString("Data"), []byte("key3"), []byte("value3"))
+	batch.DeleteBucket(db.BucketString("Missing"))
+
+	err = batch.Commit()
+	if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+		t.Errorf("Batch commit did not fail with ErrBucketNotFound. Error: %v", err)
+	}
+
+	exists, err := db.BucketString("Data").ExistsString("key3")
+	if err != nil {
+		t.Errorf("Unable to check key. Error: %s", err.Error())
+	}
+
+	if exists {
+		t.Error("Key was inserted although the batch failed")
+	}
+
+	if batch.Len() != 2 {
+		t.Errorf("Batch holds %d operations instead of keeping them after a failed commit", batch.Len())
+	}
+}
+
+func TestBatchSavepoint(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Making bucket Audit write-once with key entry1")
+	err = db.BucketString("Audit").InsertString("entry1", "old")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	err = db.BucketString("Audit").SetWriteOnce()
+	if err != nil {
+		t.Errorf("Unable to make bucket write-once. Error: %s", err.Error())
+	}
+
+	errSkipped := errors.New("skipped")
+	batch := db.NewBatch()
+	batch.Put(db.BucketString("Users"), []byte("user1"), []byte("Alice"))
+
+	t.Log("Queueing a savepoint that fails on commit, with a nested savepoint")
+	failing := batch.Savepoint(func(batch *mbuckets.Batch) error {
+		batch.Put(db.BucketString("Stats"), []byte("users"), []byte("1"))
+		batch.Savepoint(func(batch *mbuckets.Batch) error {
+			batch.Put(db.BucketString("Stats"), []byte("nested"), []byte("1"))
+			return nil
+		})
+		batch.Put(db.BucketString("Audit"), []byte("entry1"), []byte("new"))
+		return nil
+	})
+
+	t.Log("Queueing a savepoint that fails while queueing")
+	skipped := batch.Savepoint(func(batch *mbuckets.Batch) error {
+		batch.Put(db.BucketString("Users"), []byte("skipped"), []byte("value"))
+		return errSkipped
+	})
+
+	t.Log("Queueing a savepoint that succeeds")
+	succeeding := batch.Savepoint(func(batch *mbuckets.Batch) error {
+		batch.Put(db.BucketString("Users"), []byte("user2"), []byte("Bob"))
+		return nil
+	})
+
+	t.Log("Committing batch")
+	err = batch.Commit()
+	if err != nil {
+		t.Errorf("Unable to commit batch. Error: %s", err.Error())
+	}
+
+	if !errors.Is(failing.Err, mbuckets.ErrWriteOnce) {
+		t.Errorf("Failing savepoint error is not ErrWriteOnce. Error: %v", failing.Err)
+	}
+
+	if skipped.Err != errSkipped {
+		t.Errorf("Skipped savepoint error does not match the returned error. Error: %v", skipped.Err)
+	}
+
+	if succeeding.Err != nil {
+		t.Errorf("Succeeding savepoint has an error. Error: %s", succeeding.Err.Error())
+	}
+
+	keys, err := db.BucketString("Users").GetAllKeys()
+	if err != nil {
+		t.Errorf("Unable to retrieve keys. Error: %s", err.Error())
+	}
+
+	if len(keys) != 2 || string(keys[0]) != "user1" || string(keys[1]) != "user2" {
+		t.Errorf("Keys %q do not match the operations outside the rolled back savepoints", keys)
+	}
+
+	exists, err := db.BucketString("Stats").BucketExists()
+	if err != nil {
+		t.Errorf("Unable to check bucket. Error: %s", err.Error())
+	}
+
+	if exists {
+		t.Error("Operations of the rolled back savepoint were applied")
+	}
+}
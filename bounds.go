@@ -0,0 +1,137 @@
+package mbuckets
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/boltdb/bolt"
+)
+
+// First retrieves the key/value pair with the smallest key from the bolt.Bucket specified by this Bucket.
+// An error wrapping ErrKeyNotFound is returned if the bolt.Bucket holds no key/value pairs.
+func (b *Bucket) First() (Item, error) {
+	return b.boundary(func(cursor *bolt.Cursor) ([]byte, []byte) {
+		k, v := cursor.First()
+		for k != nil && v == nil {
+			k, v = cursor.Next()
+		}
+		return k, v
+	})
+}
+
+// Last retrieves the key/value pair with the largest key from the bolt.Bucket specified by this Bucket.
+// An error wrapping ErrKeyNotFound is returned if the bolt.Bucket holds no key/value pairs.
+func (b *Bucket) Last() (Item, error) {
+	return b.boundary(func(cursor *bolt.Cursor) ([]byte, []byte) {
+		k, v := cursor.Last()
+		for k != nil && v == nil {
+			k, v = cursor.Prev()
+		}
+		return k, v
+	})
+}
+
+// MinKey retrieves the smallest key from the bolt.Bucket specified by this Bucket, see First
+func (b *Bucket) MinKey() ([]byte, error) {
+	item, err := b.First()
+	return item.Key, err
+}
+
+// MaxKey retrieves the largest key from the bolt.Bucket specified by this Bucket, see Last
+func (b *Bucket) MaxKey() ([]byte, error) {
+	item, err := b.Last()
+	return item.Key, err
+}
+
+// Floor retrieves the largest key not greater than the given key from the bolt.Bucket specified by this Bucket.
+// An error wrapping ErrKeyNotFound is returned if there is no such key.
+func (b *Bucket) Floor(key []byte) ([]byte, error) {
+	var floor []byte
+	if b.withIndexedKeys(func(keys [][]byte) {
+		idx := searchKeys(keys, key)
+		if idx < len(keys) && bytes.Equal(keys[idx], key) {
+			floor = copyKey(keys[idx])
+		} else if idx > 0 {
+			floor = copyKey(keys[idx-1])
+		}
+	}) {
+		if floor == nil {
+			return nil, keyNotFound(key)
+		}
+		return floor, nil
+	}
+
+	item, err := b.boundary(func(cursor *bolt.Cursor) ([]byte, []byte) {
+		k, v := cursor.Seek(key)
+		if k == nil {
+			k, v = cursor.Last()
+		} else if !bytes.Equal(k, key) {
+			k, v = cursor.Prev()
+		}
+
+		for k != nil && v == nil {
+			k, v = cursor.Prev()
+		}
+		return k, v
+	})
+
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, keyNotFound(key)
+	}
+
+	return item.Key, err
+}
+
+// Ceiling retrieves the smallest key not less than the given key from the bolt.Bucket specified by this Bucket.
+// An error wrapping ErrKeyNotFound is returned if there is no such key.
+func (b *Bucket) Ceiling(key []byte) ([]byte, error) {
+	var ceiling []byte
+	if b.withIndexedKeys(func(keys [][]byte) {
+		if idx := searchKeys(keys, key); idx < len(keys) {
+			ceiling = copyKey(keys[idx])
+		}
+	}) {
+		if ceiling == nil {
+			return nil, keyNotFound(key)
+		}
+		return ceiling, nil
+	}
+
+	item, err := b.boundary(func(cursor *bolt.Cursor) ([]byte, []byte) {
+		k, v := cursor.Seek(key)
+		for k != nil && v == nil {
+			k, v = cursor.Next()
+		}
+		return k, v
+	})
+
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, keyNotFound(key)
+	}
+
+	return item.Key, err
+}
+
+func copyKey(key []byte) []byte {
+	keyCopy := make([]byte, len(key))
+	copy(keyCopy, key)
+	return keyCopy
+}
+
+// boundary retrieves a copy of the key/value pair found by function `find`, skipping nested bolt.Buckets
+func (b *Bucket) boundary(find func(*bolt.Cursor) ([]byte, []byte)) (item Item, err error) {
+	err = b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		k, v := find(bucket.Cursor())
+		if k == nil {
+			return bucketEmpty(b.Name)
+		}
+
+		item.Key = make([]byte, len(k))
+		copy(item.Key, k)
+		item.Value = make([]byte, len(v))
+		copy(item.Value, v)
+		return nil
+	})
+
+	return item, err
+}
@@ -0,0 +1,64 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestFirstLast(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	for _, name := range []string{"Bucket1/a", "Bucket1/z"} {
+		t.Logf("Creating nested bucket: %s", name)
+		err = db.BucketString(name).CreateBucket()
+		if err != nil {
+			t.Errorf("Unable to create nested bucket. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Retrieving the first item of a bucket without key/value pairs")
+	_, err = bucket.First()
+	if !errors.Is(err, mbuckets.ErrKeyNotFound) {
+		t.Errorf("First of a bucket without key/value pairs did not return ErrKeyNotFound. Error: %v", err)
+	}
+
+	items := map[string]string{"key2": "value2", "key1": "value1", "key3": "value3"}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	first, err := bucket.First()
+	if err != nil || string(first.Key) != "key1" || string(first.Value) != "value1" {
+		t.Errorf("First item %s does not match the smallest key. Error: %v", first, err)
+	}
+
+	last, err := bucket.Last()
+	if err != nil || string(last.Key) != "key3" || string(last.Value) != "value3" {
+		t.Errorf("Last item %s does not match the largest key. Error: %v", last, err)
+	}
+
+	min, err := bucket.MinKey()
+	if err != nil || string(min) != "key1" {
+		t.Errorf("Min key %s does not match the smallest key. Error: %v", min, err)
+	}
+
+	max, err := bucket.MaxKey()
+	if err != nil || string(max) != "key3" {
+		t.Errorf("Max key %s does not match the largest key. Error: %v", max, err)
+	}
+}
@@ -0,0 +1,67 @@
+package mbuckets
+
+// EnableBucketNameCache turns on caching of the results of GetAllBucketNames and GetAllBucketNamesWithSeparator.
+//
+// The cache is invalidated whenever a bucket is created or deleted through mbuckets,
+// and after every function passed to Update, Batch or Bucket.Update.
+// Buckets created or deleted directly through the embedded bolt.DB, or through the embedded bolt.Tx of a Tx, are not tracked,
+// call InvalidateBucketNameCache after making such changes.
+func (db *DB) EnableBucketNameCache() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.bucketNameCache == nil {
+		db.bucketNameCache = make(map[string][][]byte)
+	}
+}
+
+// DisableBucketNameCache turns off caching of bucket names and drops any cached names
+func (db *DB) DisableBucketNameCache() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.bucketNameCache = nil
+}
+
+// InvalidateBucketNameCache drops all cached bucket names
+func (db *DB) InvalidateBucketNameCache() {
+	db.structureChanged(nil, false)
+}
+
+func (db *DB) cachedBucketNames(separator []byte) ([][]byte, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	names, ok := db.bucketNameCache[string(separator)]
+	if !ok {
+		return nil, false
+	}
+
+	return copyNames(names), true
+}
+
+// cacheBucketNames stores names scanned at the given generation, unless the structure has changed since
+func (db *DB) cacheBucketNames(separator []byte, names [][]byte, generation uint64) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.bucketNameCache == nil || db.generations[pathKey(nil)] != generation {
+		return
+	}
+
+	db.bucketNameCache[string(separator)] = copyNames(names)
+}
+
+func copyNames(names [][]byte) [][]byte {
+	if names == nil {
+		return nil
+	}
+
+	namesCopy := make([][]byte, len(names))
+	for idx, name := range names {
+		namesCopy[idx] = make([]byte, len(name))
+		copy(namesCopy[idx], name)
+	}
+
+	return namesCopy
+}
@@ -0,0 +1,130 @@
+package mbuckets_test
+
+import (
+	"testing"
+
+	"github.com/boltdb/bolt"
+)
+
+func TestBucketNameCache(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Enabling bucket name cache")
+	db.EnableBucketNameCache()
+
+	bucketName1 := []byte("Bucket1/Bucket2")
+	bucket1 := db.Bucket(bucketName1)
+
+	t.Logf("Creating Bucket: %s", bucketName1)
+	err = bucket1.CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	for i := 0; i < 2; i++ {
+		t.Log("Retrieving all bucket names")
+		bucketNames, err := db.GetAllBucketNames()
+		if err != nil {
+			t.Errorf("Unable to get bucket names from db. Error: %s", err.Error())
+		}
+
+		if len(bucketNames) != 2 {
+			t.Error("Number of buckets in db do not match the expected count")
+		}
+	}
+
+	bucketName2 := []byte("Bucket3")
+	bucket2 := db.Bucket(bucketName2)
+
+	t.Logf("Inserting a key/value pair in new Bucket: %s", bucketName2)
+	err = bucket2.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving all bucket names after creation")
+	bucketNames, err := db.GetAllBucketNames()
+	if err != nil {
+		t.Errorf("Unable to get bucket names from db. Error: %s", err.Error())
+	}
+
+	if len(bucketNames) != 3 {
+		t.Error("Cached bucket names were not invalidated after creation of bucket")
+	}
+
+	t.Logf("Deleting Bucket: %s", bucketName1)
+	err = bucket1.DeleteBucket()
+	if err != nil {
+		t.Errorf("Unable to delete bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving all bucket names after deletion")
+	bucketNames, err = db.GetAllBucketNames()
+	if err != nil {
+		t.Errorf("Unable to get bucket names from db. Error: %s", err.Error())
+	}
+
+	if len(bucketNames) != 2 {
+		t.Error("Cached bucket names were not invalidated after deletion of bucket")
+	}
+}
+
+func TestBucketNameCacheUpdate(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	err = db.BucketString("Bucket1/Bucket2").CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	db.EnableBucketNameCache()
+	names, err := db.GetAllBucketNames()
+	if err != nil || len(names) != 2 {
+		t.Errorf("Bucket names %s do not match the buckets created. Error: %v", names, err)
+	}
+	generation := db.BucketString("Bucket1/Bucket2").Generation()
+
+	t.Log("Creating a bucket through the bolt.Tx passed to Update")
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucket([]byte("Bucket3"))
+		return err
+	})
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	names, err = db.GetAllBucketNames()
+	if err != nil || len(names) != 3 {
+		t.Errorf("Cached bucket names %s do not include the bucket created by Update. Error: %v", names, err)
+	}
+
+	t.Log("Creating a bucket through the bolt.Bucket passed to Bucket.Update")
+	err = db.BucketString("Bucket1/Bucket2").Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		_, err := bucket.CreateBucket([]byte("Bucket4"))
+		return err
+	})
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	names, err = db.GetAllBucketNames()
+	if err != nil || len(names) != 4 {
+		t.Errorf("Cached bucket names %s do not include the bucket created by Bucket.Update. Error: %v", names, err)
+	}
+
+	if db.BucketString("Bucket1/Bucket2").Generation() == generation {
+		t.Errorf("Generation of the bucket was not incremented by Update and Bucket.Update")
+	}
+}
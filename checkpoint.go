@@ -0,0 +1,164 @@
+package mbuckets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/boltdb/bolt"
+)
+
+// ResumeToken identifies the checkpoint of an interrupted long running operation
+type ResumeToken string
+
+// ResumableError is returned by a long running operation that failed after making progress.
+// Passing Token back to the same operation continues it from its last checkpoint.
+type ResumableError struct {
+	Err   error
+	Token ResumeToken
+}
+
+func (e *ResumableError) Error() string {
+	return fmt.Sprintf("%s (resume token: %s)", e.Err, e.Token)
+}
+
+// Unwrap returns the error that interrupted the operation
+func (e *ResumableError) Unwrap() error {
+	return e.Err
+}
+
+// Name of the meta bucket holding checkpoints
+var checkpointsBucketName = []byte("checkpoints")
+
+// Kinds of operations that can be checkpointed
+const (
+	checkpointImport = "import"
+)
+
+// checkpoint is the persisted progress of an operation on a target bucket
+type checkpoint struct {
+	Token  ResumeToken
+	Kind   string
+	Target []byte
+
+	// Number of units of work completed
+	Done uint64
+}
+
+// loadCheckpoint returns the checkpoint for the given token, or a new unsaved checkpoint if token is empty
+func (db *DB) loadCheckpoint(token ResumeToken, kind string, target []byte) (*checkpoint, error) {
+	cp := &checkpoint{Kind: kind, Target: target}
+	if token == "" {
+		return cp, nil
+	}
+
+	err := db.View(func(tx *bolt.Tx) error {
+		bucket, err := metaBucket(tx, checkpointsBucketName)
+		if err != nil {
+			return err
+		}
+
+		var value []byte
+		if bucket != nil {
+			value = bucket.Get([]byte(token))
+		}
+
+		if value == nil {
+			return fmt.Errorf("Checkpoint not found: %s", token)
+		}
+
+		return cp.decode(token, value)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cp.Kind != kind || !bytes.Equal(cp.Target, target) {
+		return nil, fmt.Errorf("Checkpoint %s belongs to %s of %s", token, cp.Kind, cp.Target)
+	}
+
+	return cp, nil
+}
+
+// advance adds done units of work to the checkpoint and saves it in the given transaction
+func (cp *checkpoint) advance(tx *bolt.Tx, done uint64) error {
+	bucket, err := metaBucket(tx, checkpointsBucketName)
+	if err != nil {
+		return err
+	}
+
+	token := cp.Token
+	if token == "" {
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		token = ResumeToken(cp.Kind + "-" + strconv.FormatUint(seq, 10))
+	}
+
+	err = bucket.Put([]byte(token), cp.encode(cp.Done+done))
+	if err != nil {
+		return err
+	}
+
+	// Only update in memory once the write is part of the transaction,
+	// the transaction may still be rolled back by the caller
+	tx.OnCommit(func() {
+		cp.Token = token
+		cp.Done += done
+	})
+
+	return nil
+}
+
+// resumable wraps err in a *ResumableError if the checkpoint has been saved
+func (cp *checkpoint) resumable(err error) error {
+	if cp.Token == "" {
+		return err
+	}
+
+	return &ResumableError{err, cp.Token}
+}
+
+// finish removes the saved checkpoint of a completed operation in the given transaction
+func (cp *checkpoint) finish(tx *bolt.Tx) error {
+	if cp.Token == "" {
+		return nil
+	}
+
+	bucket, err := metaBucket(tx, checkpointsBucketName)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Delete([]byte(cp.Token))
+}
+
+func (cp *checkpoint) encode(done uint64) []byte {
+	value := binary.AppendUvarint(nil, done)
+	value = binary.AppendUvarint(value, uint64(len(cp.Kind)))
+	value = append(value, cp.Kind...)
+	return append(value, cp.Target...)
+}
+
+func (cp *checkpoint) decode(token ResumeToken, value []byte) error {
+	done, n := binary.Uvarint(value)
+	if n <= 0 {
+		return fmt.Errorf("Invalid checkpoint: %s", token)
+	}
+	value = value[n:]
+
+	kindLen, n := binary.Uvarint(value)
+	if n <= 0 || uint64(len(value)-n) < kindLen {
+		return fmt.Errorf("Invalid checkpoint: %s", token)
+	}
+	value = value[n:]
+
+	cp.Token = token
+	cp.Done = done
+	cp.Kind = string(value[:kindLen])
+	cp.Target = append([]byte{}, value[kindLen:]...)
+	return nil
+}
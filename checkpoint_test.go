@@ -0,0 +1,110 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+// failingReader returns an error after reading limit bytes
+type failingReader struct {
+	r     io.Reader
+	limit int
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.limit <= 0 {
+		return 0, errors.New("Simulated read failure")
+	}
+
+	if len(p) > f.limit {
+		p = p[:f.limit]
+	}
+
+	n, err := f.r.Read(p)
+	f.limit -= n
+	return n, err
+}
+
+func TestImportResume(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	srcBucket := db.BucketString("Source")
+	items := make(map[string]string, 2500)
+	for i := 0; i < 2500; i++ {
+		items[fmt.Sprintf("key%04d", i)] = fmt.Sprintf("value%04d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = srcBucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	err = srcBucket.ExportItems(&buf, mbuckets.FormatBinary)
+	if err != nil {
+		t.Errorf("Unable to export items from bucket. Error: %s", err.Error())
+	}
+
+	dstBucket := db.BucketString("Destination")
+
+	t.Log("Importing items from a reader failing midway")
+	reader := &failingReader{bytes.NewReader(buf.Bytes()), buf.Len() * 3 / 4}
+	_, err = dstBucket.ImportItemsWith(reader, mbuckets.FormatBinary, nil)
+
+	var resumable *mbuckets.ResumableError
+	if !errors.As(err, &resumable) {
+		t.Fatalf("Interrupted import did not return a resume token. Error: %v", err)
+	}
+	t.Logf("Import interrupted with resume token: %s", resumable.Token)
+
+	count, err := dstBucket.EstimateCount()
+	if err != nil {
+		t.Errorf("Unable to count keys in bucket. Error: %s", err.Error())
+	}
+
+	if count == 0 || count == len(items) {
+		t.Errorf("Interrupted import wrote an unexpected number of keys: %d", count)
+	}
+
+	t.Log("Resuming import")
+	_, err = dstBucket.ImportItemsWith(bytes.NewReader(buf.Bytes()), mbuckets.FormatBinary, &mbuckets.ImportOptions{Resume: resumable.Token})
+	if err != nil {
+		t.Errorf("Unable to resume import. Error: %s", err.Error())
+	}
+
+	results, err := dstBucket.GetAllString()
+	if err != nil {
+		t.Errorf("Unable to retrieve key/value pairs from bucket. Error: %s", err.Error())
+	}
+
+	if fmt.Sprint(results) != fmt.Sprint(items) {
+		t.Error("Key/value pairs after resumed import do not match the ones exported")
+	}
+
+	t.Log("Resuming a completed import")
+	_, err = dstBucket.ImportItemsWith(bytes.NewReader(buf.Bytes()), mbuckets.FormatBinary, &mbuckets.ImportOptions{Resume: resumable.Token})
+	if err == nil {
+		t.Error("Checkpoint of completed import was not removed")
+	}
+
+	rootBucketNames, err := db.GetRootBucketNames()
+	if err != nil {
+		t.Errorf("Unable to get root bucket names from db. Error: %s", err.Error())
+	}
+
+	if len(rootBucketNames) != 2 {
+		t.Error("Internal buckets are listed among root buckets")
+	}
+}
@@ -0,0 +1,36 @@
+package mbuckets
+
+import "time"
+
+// Clock provides the current time to a DB
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SetClock sets the Clock used by this DB to measure the durations reported in CommitInfo and Events.
+// Passing nil restores the system clock.
+func (db *DB) SetClock(clock Clock) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.clock = clock
+}
+
+// now returns the current time of the Clock of this DB
+func (db *DB) now() time.Time {
+	db.mu.RLock()
+	clock := db.clock
+	db.mu.RUnlock()
+
+	if clock == nil {
+		return systemClock{}.Now()
+	}
+
+	return clock.Now()
+}
@@ -0,0 +1,59 @@
+package mbuckets_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+// fakeClock advances by a fixed step every time it is read
+type fakeClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func TestSetClock(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Setting a fake clock advancing by 1s per read")
+	db.SetClock(&fakeClock{step: time.Second})
+
+	var infos []mbuckets.CommitInfo
+	db.OnCommitInfo(func(info mbuckets.CommitInfo) {
+		infos = append(infos, info)
+	})
+
+	sink := &testEventSink{}
+	db.SetEventSink(sink)
+
+	err = db.BucketString("Bucket1").InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	if len(infos) != 1 || infos[0].Duration != time.Second {
+		t.Errorf("Commit durations %v were not measured with the fake clock", infos)
+	}
+
+	events := sink.events
+	if len(events) != 1 || events[0].Duration != 3*time.Second {
+		t.Errorf("Event durations %v were not measured with the fake clock", events)
+	}
+}
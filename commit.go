@@ -0,0 +1,207 @@
+package mbuckets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// CommitInfo describes a committed write transaction
+type CommitInfo struct {
+	// Complete hierarchial name of the Bucket the transaction was started for, nil for DB level transactions
+	Bucket []byte
+
+	// Time taken by the transaction, from its start to the end of its commit
+	Duration time.Duration
+
+	// Page, rebalance, split, spill and write statistics reported by bolt
+	Stats bolt.TxStats
+}
+
+// OnCommitInfo sets function `fn` to be called with the CommitInfo of every write transaction committed through this DB.
+// Passing nil stops reporting.
+//
+// Function `fn` is called after the transaction has been committed, on the goroutine that ran it.
+func (db *DB) OnCommitInfo(fn func(CommitInfo)) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.commitInfoHook = fn
+}
+
+// Update executes function `fn` within a read-write bolt.Tx, see bolt.DB.Update.
+//
+// If the transaction fails because bolt could not grow or remap the database file, it is retried once,
+// so function `fn` must be idempotent. An error wrapping ErrRemap is returned if the retry fails the same way.
+func (db *DB) Update(fn func(*bolt.Tx) error) error {
+	defer db.structureChanged(nil, true)
+	defer db.invalidateKeyIndexes(nil)
+
+	return db.update(nil, fn)
+}
+
+// Batch executes function `fn` within a read-write bolt.Tx shared with concurrent calls to Batch, see bolt.DB.Batch.
+//
+// Function `fn` may be called more than once, and must be idempotent. CommitInfo is not reported for batched transactions.
+func (db *DB) Batch(fn func(*bolt.Tx) error) error {
+	defer db.structureChanged(nil, true)
+	defer db.invalidateKeyIndexes(nil)
+
+	return db.batch(nil, fn)
+}
+
+// View executes function `fn` within a read-only bolt.Tx, see bolt.DB.View
+func (db *DB) View(fn func(*bolt.Tx) error) error {
+	return db.view(nil, fn)
+}
+
+// view executes function `fn` within a read-only bolt.Tx started for the bucket with the given name
+func (db *DB) view(name []byte, fn func(*bolt.Tx) error) error {
+	db.mu.RLock()
+	recoverPanics := db.recoverPanics
+	closed := db.closed
+	db.mu.RUnlock()
+
+	if closed {
+		return ErrDBClosed
+	}
+
+	if recoverPanics {
+		fn = guardTx(name, fn)
+	}
+
+	fn, finish := db.recordTx(name, false, fn)
+
+	err := closedError(db.DB.View(fn))
+	if hookErr := finish(err); err == nil {
+		err = hookErr
+	}
+	return err
+}
+
+// batch executes function `fn` within a read-write bolt.Tx shared by a batch, for the bucket with the given name
+func (db *DB) batch(name []byte, fn func(*bolt.Tx) error) error {
+	db.mu.RLock()
+	recoverPanics := db.recoverPanics
+	closed := db.closed
+	db.mu.RUnlock()
+
+	if closed {
+		return ErrDBClosed
+	}
+
+	if recoverPanics {
+		fn = guardTx(name, fn)
+	}
+
+	return closedError(db.DB.Batch(fn))
+}
+
+// update executes function `fn` within a read-write bolt.Tx started for the bucket with the given name
+func (db *DB) update(name []byte, fn func(*bolt.Tx) error) (err error) {
+	fn, finish := db.recordTx(name, true, fn)
+	defer func() {
+		if hookErr := finish(err); err == nil {
+			err = hookErr
+		}
+	}()
+
+	err = db.updateOnce(name, fn)
+	if !isRemapError(err) {
+		return err
+	}
+
+	err = db.updateOnce(name, fn)
+	if isRemapError(err) {
+		return fmt.Errorf("%w: %s", ErrRemap, err)
+	}
+
+	return err
+}
+
+func (db *DB) updateOnce(name []byte, fn func(*bolt.Tx) error) error {
+	db.mu.RLock()
+	hook := db.commitInfoHook
+	recoverPanics := db.recoverPanics
+	failpoints := db.failpoints
+	closed := db.closed
+	timeout := db.writeTimeout
+	db.mu.RUnlock()
+
+	if closed {
+		return ErrDBClosed
+	}
+
+	if recoverPanics {
+		fn = guardTx(name, fn)
+	}
+
+	var committed *bolt.Tx
+	start := db.now()
+
+	err := db.DB.Update(func(tx *bolt.Tx) error {
+		committed = tx
+
+		err := fn(tx)
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			if timeoutErr := db.checkTimeout(name, start, timeout); timeoutErr != nil {
+				err = timeoutErr
+			}
+		}
+
+		if err == nil && failpoints.Commit != nil {
+			err = failpoints.Commit(name)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return closedError(err)
+	}
+
+	if failpoints.Sync != nil {
+		if err := failpoints.Sync(name); err != nil {
+			return err
+		}
+	}
+
+	if hook == nil {
+		return nil
+	}
+
+	info := CommitInfo{Bucket: name, Duration: db.now().Sub(start), Stats: committed.Stats()}
+	return db.callHook(name, func() {
+		hook(info)
+	})
+}
+
+// closedError converts the error returned by bolt for a closed database into ErrDBClosed
+func closedError(err error) error {
+	if err == bolt.ErrDatabaseNotOpen {
+		return ErrDBClosed
+	}
+
+	return err
+}
+
+// Prefixes of the errors returned by bolt when growing or remapping the database file fails
+var remapErrorPrefixes = []string{"mmap allocate error", "file resize error", "unmap error"}
+
+func isRemapError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, prefix := range remapErrorPrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestCommitInfo(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	var infos []mbuckets.CommitInfo
+	db.OnCommitInfo(func(info mbuckets.CommitInfo) {
+		infos = append(infos, info)
+	})
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	t.Log("Inserting a key/value pair")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Reading a key/value pair")
+	_, err = bucket.GetString("key1")
+	if err != nil {
+		t.Errorf("Unable to retrieve value for given key from bucket. Error: %s", err.Error())
+	}
+
+	for _, info := range infos {
+		t.Logf("Commit for bucket: %s took %s, allocated %d pages", info.Bucket, info.Duration, info.Stats.PageCount)
+	}
+
+	if len(infos) != 1 {
+		t.Fatal("Number of commits reported does not match the number of write transactions")
+	}
+
+	if !bytes.Equal(infos[0].Bucket, bucketName) || infos[0].Stats.Write == 0 {
+		t.Error("Commit reported does not describe the write transaction")
+	}
+}
+
+func TestUpdateRetryOnRemapError(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	attempts := 0
+	t.Log("Running an update failing with a remap error once")
+	err = bucket.Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("mmap allocate error: simulated")
+		}
+		return bucket.Put([]byte("key1"), []byte("value1"))
+	})
+	if err != nil {
+		t.Errorf("Update was not retried after a remap error. Error: %s", err.Error())
+	}
+
+	attempts = 0
+	t.Log("Running an update always failing with a remap error")
+	err = bucket.Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		attempts++
+		return errors.New("mmap allocate error: simulated")
+	})
+
+	if attempts != 2 {
+		t.Errorf("Update was attempted %d times instead of twice", attempts)
+	}
+
+	if !errors.Is(err, mbuckets.ErrRemap) {
+		t.Errorf("Persistent remap error was not reported as ErrRemap. Error: %v", err)
+	}
+}
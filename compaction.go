@@ -0,0 +1,128 @@
+package mbuckets
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// CompactionPolicy configures AdviseCompaction and WatchCompaction, a zero value disables a check
+type CompactionPolicy struct {
+	// Maximum ratio of free pages to all pages in the database, between 0 and 1
+	FreePageRatio float64
+
+	// Maximum number of bytes held by free pages
+	FreeBytes int64
+
+	// Maximum number of keys deleted through the Buckets of the DB since it was opened
+	DeletedKeys uint64
+
+	// Window during which WatchCompaction reports compaction, as offsets from midnight in local time.
+	// The window may wrap around midnight. Equal offsets allow compaction at any time.
+	WindowStart time.Duration
+	WindowEnd   time.Duration
+}
+
+// CompactionAdvice describes whether a DB is worth compacting, by copying its live data into a new file
+type CompactionAdvice struct {
+	// Whether any limit of the CompactionPolicy is exceeded
+	Recommended bool
+
+	// Whether the current time of the DB Clock is within the window of the CompactionPolicy
+	InWindow bool
+
+	// An Alert for every limit exceeded, with ActionCompact
+	Alerts []Alert
+
+	// The measured values
+	FreePageRatio float64
+	FreeBytes     int64
+	DeletedKeys   uint64
+}
+
+// DeletedKeys returns the number of keys deleted through the Buckets of this DB since it was opened.
+// Keys deleted in transactions that were rolled back afterwards are counted as well.
+func (db *DB) DeletedKeys() uint64 {
+	return db.deletedKeys.Load()
+}
+
+// countDeletes counts keys deleted through a Bucket in the given transaction
+func (db *DB) countDeletes(tx *bolt.Tx, deletes int) {
+	db.deletedKeys.Add(uint64(deletes))
+
+	db.mu.RLock()
+	stat := db.txStats[tx]
+	db.mu.RUnlock()
+
+	if stat != nil {
+		stat.Deletes += deletes
+	}
+}
+
+// AdviseCompaction measures free pages and delete volume of this DB and recommends compaction
+// when they exceed the given policy
+func (db *DB) AdviseCompaction(policy CompactionPolicy) (CompactionAdvice, error) {
+	if db.IsClosed() {
+		return CompactionAdvice{}, ErrDBClosed
+	}
+
+	ratio, err := db.freePageRatio()
+	if err != nil {
+		return CompactionAdvice{}, err
+	}
+
+	stats := db.Stats()
+	advice := CompactionAdvice{
+		InWindow:      policy.inWindow(db.now()),
+		FreePageRatio: ratio,
+		FreeBytes:     int64(stats.FreeAlloc),
+		DeletedKeys:   db.DeletedKeys(),
+	}
+
+	if policy.FreePageRatio > 0 && advice.FreePageRatio > policy.FreePageRatio {
+		advice.Alerts = append(advice.Alerts, Alert{MetricFreePageRatio, advice.FreePageRatio, policy.FreePageRatio, ActionCompact})
+	}
+
+	if policy.FreeBytes > 0 && advice.FreeBytes > policy.FreeBytes {
+		advice.Alerts = append(advice.Alerts, Alert{MetricFreeBytes, float64(advice.FreeBytes), float64(policy.FreeBytes), ActionCompact})
+	}
+
+	if policy.DeletedKeys > 0 && advice.DeletedKeys > policy.DeletedKeys {
+		advice.Alerts = append(advice.Alerts, Alert{MetricDeletedKeys, float64(advice.DeletedKeys), float64(policy.DeletedKeys), ActionCompact})
+	}
+
+	advice.Recommended = len(advice.Alerts) > 0
+	return advice, nil
+}
+
+// WatchCompaction checks the given policy every interval in a new goroutine, and calls function `fn`
+// with the CompactionAdvice whenever compaction is recommended within the window of the policy.
+// Watching stops when the returned function is called or this DB is closed.
+// An error is returned if the interval is not positive.
+func (db *DB) WatchCompaction(policy CompactionPolicy, interval time.Duration, fn func(CompactionAdvice)) (stop func(), err error) {
+	return db.watch(interval, func() error {
+		advice, err := db.AdviseCompaction(policy)
+		if err == nil && advice.Recommended && advice.InWindow {
+			db.callHook(nil, func() {
+				fn(advice)
+			})
+		}
+		return err
+	})
+}
+
+// inWindow reports whether the given time is within the window of this policy
+func (policy CompactionPolicy) inWindow(now time.Time) bool {
+	if policy.WindowStart == policy.WindowEnd {
+		return true
+	}
+
+	year, month, day := now.Date()
+	offset := now.Sub(time.Date(year, month, day, 0, 0, 0, 0, now.Location()))
+
+	if policy.WindowStart < policy.WindowEnd {
+		return offset >= policy.WindowStart && offset < policy.WindowEnd
+	}
+
+	return offset >= policy.WindowStart || offset < policy.WindowEnd
+}
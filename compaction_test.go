@@ -0,0 +1,120 @@
+package mbuckets_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestAdviseCompaction(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	items := make(map[string]string, 500)
+	for i := 0; i < 500; i++ {
+		items[fmt.Sprintf("key%03d", i)] = fmt.Sprintf("%0100d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	policy := mbuckets.CompactionPolicy{DeletedKeys: 100}
+
+	advice, err := db.AdviseCompaction(policy)
+	if err != nil {
+		t.Errorf("Unable to advise compaction. Error: %s", err.Error())
+	}
+
+	if advice.Recommended {
+		t.Errorf("Compaction recommended before deleting keys: %+v", advice)
+	}
+
+	t.Log("Deleting key/value pairs")
+	err = bucket.Delete([]byte("key000"))
+	if err != nil {
+		t.Errorf("Unable to delete key. Error: %s", err.Error())
+	}
+
+	_, err = bucket.DeletePrefix([]byte("key1"))
+	if err != nil {
+		t.Errorf("Unable to delete prefix. Error: %s", err.Error())
+	}
+
+	if deleted := db.DeletedKeys(); deleted != 101 {
+		t.Errorf("Number of deleted keys %d does not match the keys deleted", deleted)
+	}
+
+	advice, err = db.AdviseCompaction(policy)
+	if err != nil {
+		t.Errorf("Unable to advise compaction. Error: %s", err.Error())
+	}
+
+	t.Logf("Compaction advice: %+v", advice)
+	if !advice.Recommended || !advice.InWindow || len(advice.Alerts) != 1 || advice.Alerts[0].Metric != mbuckets.MetricDeletedKeys {
+		t.Errorf("Compaction not recommended for deleted keys: %+v", advice)
+	}
+
+	t.Log("Setting the clock outside the compaction window")
+	db.SetClock(&fakeClock{now: time.Date(2020, 1, 1, 12, 0, 0, 0, time.Local)})
+
+	policy.WindowStart = 22 * time.Hour
+	policy.WindowEnd = 4 * time.Hour
+
+	advice, err = db.AdviseCompaction(policy)
+	if err != nil {
+		t.Errorf("Unable to advise compaction. Error: %s", err.Error())
+	}
+
+	if !advice.Recommended || advice.InWindow {
+		t.Errorf("Compaction advice does not match the window: %+v", advice)
+	}
+
+	t.Log("Setting the clock inside the compaction window")
+	db.SetClock(&fakeClock{now: time.Date(2020, 1, 1, 23, 0, 0, 0, time.Local)})
+
+	advice, err = db.AdviseCompaction(policy)
+	if err != nil {
+		t.Errorf("Unable to advise compaction. Error: %s", err.Error())
+	}
+
+	if !advice.InWindow {
+		t.Errorf("Compaction advice does not match the window wrapping around midnight: %+v", advice)
+	}
+
+	t.Log("Watching compaction with the clock inside the window")
+	advices := make(chan mbuckets.CompactionAdvice, 10)
+	stop, err := db.WatchCompaction(policy, 10*time.Millisecond, func(advice mbuckets.CompactionAdvice) {
+		advices <- advice
+	})
+	if err != nil {
+		t.Fatalf("Unable to watch compaction. Error: %s", err.Error())
+	}
+	defer stop()
+
+	select {
+	case advice := <-advices:
+		if !advice.Recommended || !advice.InWindow {
+			t.Errorf("Compaction advice %+v does not recommend compaction in the window", advice)
+		}
+	case <-time.After(time.Second):
+		t.Error("No compaction advice received while watching")
+	}
+
+	t.Log("Watching compaction with an invalid interval")
+	_, err = db.WatchCompaction(policy, -time.Second, func(mbuckets.CompactionAdvice) {})
+	if err == nil {
+		t.Error("Watching compaction with a negative interval did not fail")
+	}
+}
@@ -0,0 +1,98 @@
+package mbuckets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ProcessConcurrently calls function `fn` with every key/value pair in the bolt.Bucket specified by this Bucket,
+// excluding nested bolt.Buckets, on a pool of the given number of worker goroutines.
+//
+// The key/value pairs are read in a single read-only transaction and copied before they are handed to the workers,
+// so function `fn` may keep them and never touches memory owned by bolt. Items are processed in no particular order.
+//
+// Processing stops at the first error returned by function `fn`, which is then returned,
+// or when the context is done, in which case the context error is returned.
+func (b *Bucket) ProcessConcurrently(ctx context.Context, workers int, fn func(Item) error) error {
+	if workers < 1 {
+		return fmt.Errorf("Invalid number of workers: %d", workers)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var fnErr error
+
+	b.DB.mu.RLock()
+	recoverPanics := b.DB.recoverPanics
+	b.DB.mu.RUnlock()
+
+	process := fn
+	if recoverPanics {
+		process = func(item Item) error {
+			return guard(b.Name, func() error {
+				return fn(item)
+			})
+		}
+	}
+
+	items := make(chan Item, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for item := range items {
+				if ctx.Err() != nil {
+					continue
+				}
+
+				if err := process(item); err != nil {
+					once.Do(func() {
+						fnErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	// Stop reading as soon as processing stops, whatever the ScanErrorPolicy
+	scan := *b
+	scan.scanPolicy = ScanStop
+
+	err := scan.Map(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+
+		item := Item{make([]byte, len(k)), make([]byte, len(v))}
+		copy(item.Key, k)
+		copy(item.Value, v)
+
+		select {
+		case items <- item:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	close(items)
+	wg.Wait()
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return parent.Err()
+}
@@ -0,0 +1,106 @@
+package mbuckets_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestProcessConcurrently(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	items := make(map[string]string, 100)
+	for i := 0; i < 100; i++ {
+		items[fmt.Sprintf("key%03d", i)] = fmt.Sprintf("value%03d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Creating nested bucket: Bucket1/Nested")
+	err = db.BucketString("Bucket1/Nested").CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create nested bucket. Error: %s", err.Error())
+	}
+
+	var mu sync.Mutex
+	processed := make(map[string]string)
+
+	t.Log("Processing key/value pairs with 4 workers")
+	err = bucket.ProcessConcurrently(context.Background(), 4, func(item mbuckets.Item) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		processed[string(item.Key)] = string(item.Value)
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Unable to process key/value pairs. Error: %s", err.Error())
+	}
+
+	if len(processed) != len(items) {
+		t.Errorf("Number of processed key/value pairs %d does not match inserted %d", len(processed), len(items))
+	}
+
+	for k, v := range items {
+		if processed[k] != v {
+			t.Errorf("Processed value %s of key %s does not match inserted value %s", processed[k], k, v)
+		}
+	}
+
+	errFailed := errors.New("failed")
+	var calls int32
+
+	t.Log("Processing key/value pairs with a failing function")
+	err = bucket.ProcessConcurrently(context.Background(), 2, func(item mbuckets.Item) error {
+		atomic.AddInt32(&calls, 1)
+		return errFailed
+	})
+
+	if err != errFailed {
+		t.Errorf("Processing did not return the error of the function. Error: %v", err)
+	}
+
+	t.Logf("Function called %d times before stopping", calls)
+	if calls == int32(len(items)) {
+		t.Error("Processing did not stop at the first error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Log("Processing key/value pairs with a cancelled context")
+	err = bucket.ProcessConcurrently(ctx, 2, func(item mbuckets.Item) error {
+		return nil
+	})
+
+	if err != context.Canceled {
+		t.Errorf("Processing did not return the context error. Error: %v", err)
+	}
+
+	t.Log("Processing key/value pairs with no workers")
+	err = bucket.ProcessConcurrently(context.Background(), 0, func(item mbuckets.Item) error {
+		return nil
+	})
+
+	if err == nil {
+		t.Error("Processing with no workers did not fail")
+	}
+}
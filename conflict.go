@@ -0,0 +1,88 @@
+package mbuckets
+
+import (
+	"bytes"
+	"path"
+)
+
+// ConflictStrategy specifies how an import resolves a key that already exists in the destination bolt.Bucket
+type ConflictStrategy int
+
+const (
+	// ConflictOverwrite replaces the existing value with the imported one
+	ConflictOverwrite ConflictStrategy = iota
+
+	// ConflictSkip keeps the existing value
+	ConflictSkip
+
+	// ConflictCallback calls the Resolve function of the ConflictRule to choose the value to keep
+	ConflictCallback
+)
+
+// ConflictRule applies a ConflictStrategy to all buckets whose complete name matches Pattern.
+//
+// Pattern is split on the separator of the importing Bucket, and each segment is matched
+// against the corresponding segment of the bucket name using path.Match.
+type ConflictRule struct {
+	Pattern  []byte
+	Strategy ConflictStrategy
+
+	// Resolve returns the value to store for a conflict, or nil to keep the existing value.
+	// It is called inside the import transaction and must not use the DB.
+	Resolve func(conflict Conflict) ([]byte, error)
+}
+
+// Conflict describes an imported key that already existed in the destination bolt.Bucket
+type Conflict struct {
+	// Complete hierarchial name of the bucket holding the key
+	Bucket []byte
+
+	Key      []byte
+	Existing []byte
+	Incoming []byte
+
+	// Whether the existing value was replaced
+	Replaced bool
+}
+
+// resolve returns the value to store for the given conflict, or nil to keep the existing value
+func (options *ImportOptions) resolve(separator []byte, conflict Conflict) ([]byte, error) {
+	for _, rule := range options.Rules {
+		if !matchBucketName(rule.Pattern, conflict.Bucket, separator) {
+			continue
+		}
+
+		switch rule.Strategy {
+		case ConflictSkip:
+			return nil, nil
+		case ConflictCallback:
+			if rule.Resolve == nil {
+				return nil, nil
+			}
+			return rule.Resolve(conflict)
+		}
+
+		return conflict.Incoming, nil
+	}
+
+	return conflict.Incoming, nil
+}
+
+// matchBucketName reports whether the bucket name matches the pattern segment by segment
+func matchBucketName(pattern, name, separator []byte) bool {
+	patternSegments := bytes.Split(pattern, separator)
+	nameSegments := bytes.Split(name, separator)
+
+	if len(patternSegments) != len(nameSegments) {
+		return false
+	}
+
+	for idx, patternSegment := range patternSegments {
+		matched, err := path.Match(string(patternSegment), string(nameSegments[idx]))
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestImportConflicts(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	srcBucket := db.BucketString("Source")
+	for _, name := range []string{"Source/Users", "Source/Config"} {
+		err = db.BucketString(name).InsertString("key1", "new")
+		if err != nil {
+			t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+		}
+	}
+
+	for _, name := range []string{"Destination/Users", "Destination/Config"} {
+		err = db.BucketString(name).InsertString("key1", "old")
+		if err != nil {
+			t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+		}
+	}
+
+	var buf bytes.Buffer
+	t.Log("Exporting items")
+	err = srcBucket.ExportItems(&buf, mbuckets.FormatBinary)
+	if err != nil {
+		t.Errorf("Unable to export items from bucket. Error: %s", err.Error())
+	}
+
+	options := &mbuckets.ImportOptions{
+		Rules: []mbuckets.ConflictRule{
+			{Pattern: []byte("*/Users"), Strategy: mbuckets.ConflictSkip},
+			{Pattern: []byte("Destination/C*"), Strategy: mbuckets.ConflictCallback, Resolve: func(conflict mbuckets.Conflict) ([]byte, error) {
+				return append(conflict.Existing, conflict.Incoming...), nil
+			}},
+		},
+	}
+
+	t.Log("Importing items with conflict rules")
+	conflicts, err := db.BucketString("Destination").ImportItemsWith(&buf, mbuckets.FormatBinary, options)
+	if err != nil {
+		t.Errorf("Unable to import items in bucket. Error: %s", err.Error())
+	}
+
+	for _, conflict := range conflicts {
+		t.Logf("Conflict in bucket: %s, Key: %s, Replaced: %t", conflict.Bucket, conflict.Key, conflict.Replaced)
+	}
+
+	if len(conflicts) != 2 {
+		t.Error("Number of conflicts reported does not match the expected count")
+	}
+
+	expected := map[string]string{"Destination/Users": "old", "Destination/Config": "oldnew"}
+	for name, value := range expected {
+		result, err := db.BucketString(name).GetString("key1")
+		if err != nil {
+			t.Errorf("Unable to retrieve value for given key from bucket. Error: %s", err.Error())
+		}
+
+		if result != value {
+			t.Errorf("Value in bucket: %s does not match the one chosen by the conflict rule", name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package mbuckets
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+// Increment adds delta to the counter stored for the given key in the bolt.Bucket specified by this Bucket
+// in a single transaction, and returns the new value of the counter.
+//
+// Counters are stored as 8 byte big-endian two's complement integers. A missing key is treated as a counter at zero,
+// and an error is returned if the key holds a value of any other length.
+func (b *Bucket) Increment(key []byte, delta int64) (value int64, err error) {
+	err = b.mutate(OpIncrement, key, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		value = 0
+
+		if v := bucket.Get(key); v != nil {
+			if len(v) != 8 {
+				return fmt.Errorf("Invalid counter for key %s: %d bytes", key, len(v))
+			}
+			value = int64(binary.BigEndian.Uint64(v))
+		}
+
+		value += delta
+
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, uint64(value))
+		return b.put(bucket, key, buf)
+	})
+
+	return value, err
+}
+
+// IncrementString is a convenience wrapper over Increment for string key
+func (b *Bucket) IncrementString(key string, delta int64) (int64, error) {
+	return b.Increment([]byte(key), delta)
+}
+
+// Decrement subtracts delta from the counter stored for the given key, see Increment
+func (b *Bucket) Decrement(key []byte, delta int64) (int64, error) {
+	return b.Increment(key, -delta)
+}
+
+// DecrementString is a convenience wrapper over Decrement for string key
+func (b *Bucket) DecrementString(key string, delta int64) (int64, error) {
+	return b.Decrement([]byte(key), delta)
+}
@@ -0,0 +1,59 @@
+package mbuckets_test
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIncrement(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	key := "counter"
+
+	t.Logf("Incrementing Key: %s from 10 goroutines", key)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := bucket.IncrementString(key, 2)
+				if err != nil {
+					t.Errorf("Unable to increment counter in bucket. Error: %s", err.Error())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	t.Logf("Decrementing Key: %s", key)
+	value, err := bucket.DecrementString(key, 250)
+	if err != nil {
+		t.Errorf("Unable to decrement counter in bucket. Error: %s", err.Error())
+	}
+
+	if value != -50 {
+		t.Errorf("Counter value %d does not match the increments and decrements", value)
+	}
+
+	t.Log("Incrementing a key holding a value that is not a counter")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	_, err = bucket.IncrementString("key1", 1)
+	if err == nil {
+		t.Error("Increment of a value that is not a counter did not fail")
+	}
+}
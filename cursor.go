@@ -0,0 +1,22 @@
+package mbuckets
+
+import "github.com/boltdb/bolt"
+
+// Cursor is a bolt.Cursor over the bolt.Bucket specified by a Bucket, see Bucket.Cursor.
+//
+// As with bolt.Cursor, nested bolt.Buckets are returned with a nil value, and the returned key/value slices
+// are only valid until the function passed to Bucket.Cursor returns.
+type Cursor struct {
+	*bolt.Cursor
+}
+
+// Cursor executes function `fn` with a Cursor over the bolt.Bucket specified by this Bucket, within a read-only bolt.Tx,
+// or the Tx this Bucket is bound to. Several Cursors over different Buckets can be used together by binding the Buckets
+// to the same Tx, see DB.ViewTx.
+//
+// Deletes made through Cursor.Delete in a read-write Tx bypass the checks and tracking of Bucket.Delete.
+func (b *Bucket) Cursor(fn func(*Cursor) error) error {
+	return b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return fn(&Cursor{bucket.Cursor()})
+	})
+}
@@ -0,0 +1,105 @@
+package mbuckets_test
+
+import (
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestCursor(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Inserting key/value pairs in two buckets")
+	err = db.BucketString("Left").InsertAllString(map[string]string{"a": "1", "c": "3", "e": "5"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs. Error: %s", err.Error())
+	}
+
+	err = db.BucketString("Right").InsertAllString(map[string]string{"b": "2", "c": "30", "e": "50", "f": "60"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs. Error: %s", err.Error())
+	}
+
+	t.Log("Moving a cursor")
+	err = db.BucketString("Left").Cursor(func(c *mbuckets.Cursor) error {
+		if k, _ := c.First(); string(k) != "a" {
+			t.Errorf("First key %s does not match", k)
+		}
+
+		if k, v := c.Seek([]byte("b")); string(k) != "c" || string(v) != "3" {
+			t.Errorf("Seek returned %s/%s instead of c/3", k, v)
+		}
+
+		if k, _ := c.Next(); string(k) != "e" {
+			t.Errorf("Next key %s does not match", k)
+		}
+
+		if k, _ := c.Next(); k != nil {
+			t.Errorf("Next key %s past the last key", k)
+		}
+
+		if k, _ := c.Last(); string(k) != "e" {
+			t.Errorf("Last key %s does not match", k)
+		}
+
+		if k, _ := c.Prev(); string(k) != "c" {
+			t.Errorf("Previous key %s does not match", k)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Unable to use cursor. Error: %s", err.Error())
+	}
+
+	t.Log("Joining two buckets with cursors in one transaction")
+	var joined []string
+	err = db.ViewTx(func(tx *mbuckets.Tx) error {
+		return tx.BucketString("Left").Cursor(func(left *mbuckets.Cursor) error {
+			return tx.BucketString("Right").Cursor(func(right *mbuckets.Cursor) error {
+				lk, lv := left.First()
+				rk, rv := right.First()
+
+				for lk != nil && rk != nil {
+					switch {
+					case string(lk) < string(rk):
+						lk, lv = left.Next()
+					case string(lk) > string(rk):
+						rk, rv = right.Next()
+					default:
+						joined = append(joined, string(lk)+"="+string(lv)+","+string(rv))
+						lk, lv = left.Next()
+						rk, rv = right.Next()
+					}
+				}
+
+				return nil
+			})
+		})
+	})
+
+	if err != nil {
+		t.Errorf("Unable to join buckets. Error: %s", err.Error())
+	}
+
+	t.Logf("Joined: %v", joined)
+	if len(joined) != 2 || joined[0] != "c=3,30" || joined[1] != "e=5,50" {
+		t.Errorf("Joined pairs %v do not match", joined)
+	}
+
+	t.Log("Using a cursor on a missing bucket")
+	err = db.BucketString("Missing").Cursor(func(c *mbuckets.Cursor) error {
+		return nil
+	})
+
+	if err == nil {
+		t.Errorf("Cursor on a missing bucket did not fail")
+	}
+}
@@ -0,0 +1,47 @@
+package mbuckets
+
+import (
+	"errors"
+
+	"github.com/boltdb/bolt"
+)
+
+// errDryRun rolls back the transaction of a dry run
+var errDryRun = errors.New("Dry run")
+
+// DryRun executes function `fn` within a read-write Tx like UpdateTx, but always rolls the Tx back,
+// and returns the Mutations made through its Buckets that would have been committed.
+//
+// Function `fn` sees its own changes, so a dry run validates an import or a migration without applying it.
+// Writes made directly through the embedded bolt.Tx are rolled back as well, but are not reported.
+// The hooks set by OnCommit and OnRollback are not called, and no Events are emitted.
+func (db *DB) DryRun(fn func(*Tx) error) ([]Mutation, error) {
+	var mutations []Mutation
+
+	err := db.update(nil, func(boltTx *bolt.Tx) error {
+		defer db.finishKeyChanges(boltTx, false)
+
+		tx := &Tx{Tx: boltTx, db: db}
+
+		err := fn(tx)
+		if err != nil {
+			return err
+		}
+
+		mutations = tx.mutations
+		return errDryRun
+	})
+
+	if err != errDryRun {
+		return nil, err
+	}
+
+	return mutations, nil
+}
+
+// UpdateDryRun executes function `fn` with this Bucket bound to a read-write Tx that is always rolled back, see DB.DryRun
+func (b *Bucket) UpdateDryRun(fn func(*Bucket) error) ([]Mutation, error) {
+	return b.DB.DryRun(func(tx *Tx) error {
+		return fn(tx.Bind(b))
+	})
+}
@@ -0,0 +1,95 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting a key/value pair")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	committed := 0
+	db.OnCommit(func([]mbuckets.Mutation) {
+		committed++
+	})
+
+	t.Log("Running a migration as a dry run")
+	mutations, err := db.DryRun(func(tx *mbuckets.Tx) error {
+		err := tx.BucketString("Bucket1").MoveKeyString("key1", tx.BucketString("Bucket2"))
+		if err != nil {
+			return err
+		}
+
+		value, err := tx.BucketString("Bucket2").GetString("key1")
+		if err != nil {
+			return err
+		}
+
+		if value != "value1" {
+			t.Errorf("Moved value %s does not match within the dry run", value)
+		}
+
+		return tx.BucketString("Bucket2").InsertString("key2", "value2")
+	})
+
+	if err != nil {
+		t.Errorf("Unable to run dry run. Error: %s", err.Error())
+	}
+
+	for _, mutation := range mutations {
+		t.Logf("Mutation: %s on bucket %s, key %s", mutation.Op, mutation.Bucket, mutation.Key)
+	}
+
+	if len(mutations) != 2 || mutations[0].Op != mbuckets.OpMoveKey || mutations[1].Op != mbuckets.OpInsert {
+		t.Errorf("Mutations %v do not match the dry run", mutations)
+	}
+
+	if committed != 0 {
+		t.Error("Commit hook was called for a dry run")
+	}
+
+	value, err := bucket.GetString("key1")
+	if err != nil || value != "value1" {
+		t.Errorf("Dry run changed the value of key1 to %s. Error: %v", value, err)
+	}
+
+	exists, err := db.BucketString("Bucket2").BucketExists()
+	if err != nil {
+		t.Errorf("Unable to check bucket. Error: %s", err.Error())
+	}
+
+	if exists {
+		t.Error("Dry run created a bucket")
+	}
+
+	errFailed := errors.New("failed")
+
+	t.Log("Running a failing update as a dry run on a bucket")
+	_, err = bucket.UpdateDryRun(func(bucket *mbuckets.Bucket) error {
+		err := bucket.InsertString("key3", "value3")
+		if err != nil {
+			return err
+		}
+		return errFailed
+	})
+
+	if err != errFailed {
+		t.Errorf("Dry run did not return the error of the function. Error: %v", err)
+	}
+}
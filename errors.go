@@ -0,0 +1,63 @@
+package mbuckets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// ErrKeyNotFound is returned, wrapped with the key, when a key does not exist in a bolt.Bucket
+	ErrKeyNotFound = errors.New("Key not found")
+
+	// ErrBucketNotFound is returned, wrapped with the bucket name, when a bolt.Bucket does not exist
+	ErrBucketNotFound = errors.New("Bucket not found")
+
+	// ErrDBClosed is returned by all operations on a closed DB
+	ErrDBClosed = errors.New("Database closed")
+
+	// ErrRemap is returned when a write transaction fails twice because bolt could not grow or remap the database file
+	ErrRemap = errors.New("Database remap failed")
+
+	// ErrReservedBucket is returned, wrapped with the bucket name, by all operations on a Bucket
+	// specifying the bolt.Bucket holding the state of mbuckets itself, or a bolt.Bucket under it
+	ErrReservedBucket = errors.New("Reserved bucket name")
+)
+
+// MissingKeysError is returned by GetMany when some of the requested keys do not exist in a bolt.Bucket
+type MissingKeysError struct {
+	Keys [][]byte
+}
+
+func (e *MissingKeysError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrKeyNotFound, bytes.Join(e.Keys, []byte(", ")))
+}
+
+// Unwrap returns ErrKeyNotFound so that errors.Is matches a MissingKeysError
+func (e *MissingKeysError) Unwrap() error {
+	return ErrKeyNotFound
+}
+
+func keyNotFound(key []byte) error {
+	return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+}
+
+// bucketEmpty is returned when looking for a key in a bolt.Bucket without any key/value pairs
+func bucketEmpty(name []byte) error {
+	return fmt.Errorf("%w: Bucket %s is empty", ErrKeyNotFound, name)
+}
+
+func bucketNotFound(name []byte) error {
+	return fmt.Errorf("%w: %s", ErrBucketNotFound, name)
+}
+
+// bucketError converts bolt.ErrBucketNotFound into ErrBucketNotFound for the bucket with the given name
+func bucketError(err error, name []byte) error {
+	if err == bolt.ErrBucketNotFound {
+		return bucketNotFound(name)
+	}
+
+	return err
+}
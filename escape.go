@@ -0,0 +1,124 @@
+package mbuckets
+
+import (
+	"bytes"
+)
+
+// The byte escaping a separator, or itself, in a bucket name
+const escapeByte = '\\'
+
+// Escape returns a copy of the given bucket name in which every separator and backslash is escaped with a backslash,
+// so that it is read as a single bucket by a DB with name escaping enabled
+func Escape(name, separator []byte) []byte {
+	escaped := make([]byte, 0, len(name))
+
+	for rest := name; len(rest) > 0; {
+		switch {
+		case rest[0] == escapeByte:
+			escaped = append(escaped, escapeByte, escapeByte)
+			rest = rest[1:]
+		case len(separator) > 0 && bytes.HasPrefix(rest, separator):
+			escaped = append(escaped, escapeByte)
+			escaped = append(escaped, separator...)
+			rest = rest[len(separator):]
+		default:
+			escaped = append(escaped, rest[0])
+			rest = rest[1:]
+		}
+	}
+
+	return escaped
+}
+
+// EscapeString is a convenience wrapper over Escape for string name and separator
+func EscapeString(name, separator string) string {
+	return string(Escape([]byte(name), []byte(separator)))
+}
+
+// Unescape returns a copy of the given escaped bucket name with its escaping backslashes removed
+func Unescape(name []byte) []byte {
+	unescaped := make([]byte, 0, len(name))
+
+	for idx := 0; idx < len(name); idx++ {
+		if name[idx] == escapeByte && idx+1 < len(name) {
+			idx++
+		}
+		unescaped = append(unescaped, name[idx])
+	}
+
+	return unescaped
+}
+
+// UnescapeString is a convenience wrapper over Unescape for string name
+func UnescapeString(name string) string {
+	return string(Unescape([]byte(name)))
+}
+
+// SetNameEscaping sets whether the names of Buckets created afterwards through this DB are split
+// only at separators not escaped with a backslash, see Escape.
+// Bucket names listed by this DB and its Buckets are escaped accordingly.
+//
+// Escaping is disabled by default, so that existing bucket names containing a backslash keep their meaning.
+func (db *DB) SetNameEscaping(enabled bool) {
+	db.mu.Lock()
+	db.nameEscaping = enabled
+	db.mu.Unlock()
+
+	db.InvalidateBucketNameCache()
+}
+
+func (db *DB) escaping() bool {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.nameEscaping
+}
+
+// splitName splits the given bucket name at the given separator, honoring escapes if enabled
+func (db *DB) splitName(name, separator []byte) [][]byte {
+	if !db.escaping() {
+		return bytes.Split(name, separator)
+	}
+
+	var segments [][]byte
+	segment := make([]byte, 0, len(name))
+
+	for idx := 0; idx < len(name); idx++ {
+		switch {
+		case name[idx] == escapeByte && idx+1 < len(name):
+			idx++
+			segment = append(segment, name[idx])
+		case len(separator) > 0 && bytes.HasPrefix(name[idx:], separator):
+			segments = append(segments, segment)
+			segment = make([]byte, 0, len(name)-idx)
+			idx += len(separator) - 1
+		default:
+			segment = append(segment, name[idx])
+		}
+	}
+
+	return append(segments, segment)
+}
+
+// listedName returns the given bolt.Bucket name as it appears in bucket listings
+func (db *DB) listedName(name, separator []byte) []byte {
+	if !db.escaping() {
+		return name
+	}
+
+	return Escape(name, separator)
+}
+
+// joinNames joins the given bolt.Bucket names into a bucket name, escaping them if enabled
+func (db *DB) joinNames(names [][]byte, separator []byte) []byte {
+	if !db.escaping() {
+		return bytes.Join(names, separator)
+	}
+
+	escaped := make([][]byte, len(names))
+	for idx, name := range names {
+		escaped[idx] = Escape(name, separator)
+	}
+
+	return bytes.Join(escaped, separator)
+}
@@ -0,0 +1,69 @@
+package mbuckets_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestEscape(t *testing.T) {
+	names := []string{"plain", "2024/01", `back\slash`, `/\/`, ""}
+
+	for _, name := range names {
+		escaped := mbuckets.EscapeString(name, "/")
+		t.Logf("Name: %q, Escaped: %q", name, escaped)
+
+		if unescaped := mbuckets.UnescapeString(escaped); unescaped != name {
+			t.Errorf("Unescaped name %q does not match the name %q", unescaped, name)
+		}
+	}
+}
+
+func TestNameEscaping(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Enabling name escaping")
+	db.SetNameEscaping(true)
+
+	name := "Bucket1/" + mbuckets.EscapeString("2024/01", "/")
+	t.Logf("Inserting key/value in bucket: %s", name)
+	err = db.BucketString(name).InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	value, err := db.BucketAtString("Bucket1", "2024/01").GetString("key1")
+	if err != nil || value != "value1" {
+		t.Errorf("Escaped separator was not kept in the bucket name. Error: %v", err)
+	}
+
+	t.Log("Retrieving all bucket names")
+	names, err := db.GetAllBucketNames()
+	if err != nil {
+		t.Errorf("Unable to retrieve bucket names. Error: %s", err.Error())
+	}
+
+	if fmt.Sprintf("%s", names) != `[Bucket1 Bucket1/2024\/01]` {
+		t.Errorf("Bucket names %s are not escaped", names)
+	}
+
+	value, err = db.Bucket(names[1]).GetString("key1")
+	if err != nil || value != "value1" {
+		t.Errorf("Listed bucket name does not refer to the bucket created. Error: %v", err)
+	}
+
+	t.Log("Disabling name escaping")
+	db.SetNameEscaping(false)
+
+	names, err = db.GetAllBucketNames()
+	if err != nil || fmt.Sprintf("%s", names) != "[Bucket1 Bucket1/2024/01]" {
+		t.Errorf("Bucket names %s are escaped with escaping disabled. Error: %v", names, err)
+	}
+}
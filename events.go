@@ -0,0 +1,156 @@
+package mbuckets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Operations reported in Events
+const (
+	OpUpdate         = "update"
+	OpCreateBucket   = "create_bucket"
+	OpDeleteBucket   = "delete_bucket"
+	OpInsert         = "insert"
+	OpInsertAll      = "insert_all"
+	OpInsertIfAbsent = "insert_if_absent"
+	OpUpdateKeys     = "update_keys"
+	OpCompareAndSwap = "compare_and_swap"
+	OpDelete         = "delete"
+	OpDeleteAll      = "delete_all"
+	OpDeletePrefix   = "delete_prefix"
+	OpDeleteRange    = "delete_range"
+	OpClear          = "clear"
+	OpIncrement      = "increment"
+	OpNextID         = "next_id"
+	OpInsertNext     = "insert_next"
+	OpMoveKey        = "move_key"
+	OpRenameKey      = "rename_key"
+	OpTake           = "take"
+	OpSetWriteOnce   = "set_write_once"
+	OpImport         = "import"
+)
+
+// Event describes a single mutating call made through mbuckets
+type Event struct {
+	// One of the Op constants
+	Op string
+
+	// Complete hierarchial name of the Bucket the call was made on
+	Bucket []byte
+
+	// Hex encoded truncated SHA-256 of the key, empty for calls not about a single key
+	KeyHash string
+
+	// Time taken by the call
+	Duration time.Duration
+
+	// Error returned by the call, nil on success
+	Err error
+}
+
+// EventSink receives the Events of a DB.
+// Emit is called synchronously after each mutating call returns, and must be safe for concurrent use.
+// The Events of calls made through the Buckets of a Tx are emitted when the Tx commits, and dropped if it is rolled back.
+type EventSink interface {
+	Emit(event Event)
+}
+
+// SetEventSink sets the EventSink receiving an Event for every mutating call made through this DB.
+// Passing nil stops emitting Events.
+func (db *DB) SetEventSink(sink EventSink) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.eventSink = sink
+}
+
+// track runs a mutating call with function `fn` and emits its Event
+func (db *DB) track(op string, name, key []byte, fn func() error) error {
+	return db.trackIn(nil, op, name, key, fn)
+}
+
+// trackIn is track for a call made within Tx `tx`, whose Event is only emitted once `tx` commits, see Tx.finish.
+// The Event is emitted right away if `tx` is nil or read-only.
+func (db *DB) trackIn(tx *Tx, op string, name, key []byte, fn func() error) error {
+	db.mu.RLock()
+	sink := db.eventSink
+	db.mu.RUnlock()
+
+	if sink == nil {
+		return fn()
+	}
+
+	start := db.now()
+	err := fn()
+
+	event := Event{Op: op, Bucket: name, Duration: db.now().Sub(start)}
+	if !isHookError(err) {
+		event.Err = err
+	}
+	if key != nil {
+		sum := sha256.Sum256(key)
+		event.KeyHash = hex.EncodeToString(sum[:16])
+	}
+
+	if tx != nil && tx.Writable() {
+		tx.events = append(tx.events, event)
+		return err
+	}
+
+	emitErr := db.callHook(name, func() {
+		sink.Emit(event)
+	})
+
+	if err == nil {
+		err = emitErr
+	}
+	return err
+}
+
+// emit sends the given Events to the EventSink of this DB, and returns the first error of the sink, see DB.callHook
+func (db *DB) emit(events []Event) (err error) {
+	db.mu.RLock()
+	sink := db.eventSink
+	db.mu.RUnlock()
+
+	if sink == nil {
+		return nil
+	}
+
+	for _, event := range events {
+		emitErr := db.callHook(event.Bucket, func() {
+			sink.Emit(event)
+		})
+
+		if err == nil {
+			err = emitErr
+		}
+	}
+
+	return err
+}
+
+// track runs a mutating call on this Bucket with function `fn`, emits its Event and reports its Mutation, see OnCommit
+func (b *Bucket) track(op string, key []byte, fn func() error) error {
+	err := b.DB.trackIn(b.tx, op, b.Name, key, fn)
+
+	mutation := Mutation{Op: op, Bucket: b.Name}
+	if key != nil {
+		mutation.Key = copyKey(key)
+	}
+
+	if hookErr := b.mutated(mutation, err); err == nil {
+		err = hookErr
+	}
+	return err
+}
+
+// mutate performs an update operation specified by function `fn` on this Bucket and emits its Event
+func (b *Bucket) mutate(op string, key []byte, fn func(*bolt.Bucket, *bolt.Tx) error) error {
+	return b.track(op, key, func() error {
+		return b.update(fn)
+	})
+}
@@ -0,0 +1,144 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+type testEventSink struct {
+	mu     sync.Mutex
+	events []mbuckets.Event
+}
+
+func (s *testEventSink) Emit(event mbuckets.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func TestEventSink(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	sink := &testEventSink{}
+	db.SetEventSink(sink)
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting, reading and deleting a key/value pair")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	_, err = bucket.GetString("key1")
+	if err != nil {
+		t.Errorf("Unable to retrieve value for given key from bucket. Error: %s", err.Error())
+	}
+
+	err = bucket.DeleteString("key1")
+	if err != nil {
+		t.Errorf("Unable to delete key from bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Deleting a missing bucket")
+	err = db.BucketString("Missing/Bucket").DeleteBucket()
+	if err == nil {
+		t.Error("Deleting a missing bucket did not fail")
+	}
+
+	for _, event := range sink.events {
+		t.Logf("Event: Op = %s, Bucket = %s, KeyHash = %s, Duration = %s, Err = %v", event.Op, event.Bucket, event.KeyHash, event.Duration, event.Err)
+	}
+
+	expected := []string{mbuckets.OpInsert, mbuckets.OpDelete, mbuckets.OpDeleteBucket}
+	if len(sink.events) != len(expected) {
+		t.Fatal("Number of events emitted does not match the number of mutating calls")
+	}
+
+	for idx, op := range expected {
+		if sink.events[idx].Op != op {
+			t.Errorf("Event %d has Op: %s instead of: %s", idx, sink.events[idx].Op, op)
+		}
+	}
+
+	if sink.events[0].KeyHash == "" || sink.events[0].KeyHash != sink.events[1].KeyHash {
+		t.Error("Events for the same key do not have the same key hash")
+	}
+
+	if sink.events[2].Err == nil {
+		t.Error("Event for failed call does not report the error")
+	}
+}
+
+func TestEventSinkTx(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	sink := &testEventSink{}
+	db.SetEventSink(sink)
+
+	t.Log("Inserting in a dry run")
+	_, err = db.DryRun(func(tx *mbuckets.Tx) error {
+		return tx.BucketString("Bucket1").InsertString("key1", "value1")
+	})
+
+	if err != nil {
+		t.Errorf("Unable to run dry run. Error: %s", err.Error())
+	}
+
+	t.Log("Inserting in a failing transaction")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		err := tx.BucketString("Bucket1").InsertString("key1", "value1")
+		if err != nil {
+			return err
+		}
+		return errors.New("Abort")
+	})
+
+	if err == nil {
+		t.Errorf("Failing transaction did not return its error")
+	}
+
+	if len(sink.events) != 0 {
+		t.Errorf("Events %v were emitted for rolled back transactions", sink.events)
+	}
+
+	t.Log("Inserting in a committed transaction")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		bucket := tx.BucketString("Bucket1")
+
+		err := bucket.InsertString("key1", "value1")
+		if err != nil {
+			return err
+		}
+
+		if len(sink.events) != 0 {
+			t.Errorf("Events %v were emitted before the transaction committed", sink.events)
+		}
+
+		return bucket.InsertString("key2", "value2")
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update in transaction. Error: %s", err.Error())
+	}
+
+	if len(sink.events) != 2 || sink.events[0].Op != mbuckets.OpInsert || sink.events[1].Err != nil {
+		t.Errorf("Events %v do not match the committed inserts", sink.events)
+	}
+}
@@ -0,0 +1,689 @@
+package mbuckets
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/boltdb/bolt"
+)
+
+// ItemFormat specifies the encoding of a stream of Items
+type ItemFormat int
+
+const (
+	// FormatGob encodes each Item using encoding/gob
+	FormatGob ItemFormat = iota
+
+	// FormatNDJSON encodes each Item as a JSON object on a line of its own.
+	// Keys, values and bucket names that are not valid UTF-8 are base64 encoded.
+	FormatNDJSON
+
+	// FormatBinary encodes each record of an export as the uvarint count of its bucket names, followed by the bucket names,
+	// the key and the value, each prefixed with its uvarint length. A record without key marks a nested bucket.
+	// Streams of Items without export header hold only the length prefixed key and value of each Item.
+	FormatBinary
+)
+
+var formatNames = map[ItemFormat]string{
+	FormatGob:    "gob",
+	FormatNDJSON: "ndjson",
+	FormatBinary: "binary",
+}
+
+// String returns the name of this ItemFormat as written in export headers
+func (format ItemFormat) String() string {
+	if name, ok := formatNames[format]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("ItemFormat(%d)", int(format))
+}
+
+// Number of records written per transaction by ImportItems
+const importBatchSize = 1000
+
+// ImportOptions configures ImportItemsWith
+type ImportOptions struct {
+	// Rules are tried in order and the first matching one is used.
+	// Keys in buckets matching no rule are overwritten.
+	Rules []ConflictRule
+
+	// Resume continues an interrupted import of the same export from its last checkpoint
+	Resume ResumeToken
+
+	// Transforms are tried in order for every record, and the first one matching its bucket is applied
+	Transforms []Transform
+
+	// Maximum size in bytes of a bucket name, key or value read from a FormatBinary export, zero for bolt.MaxValueSize.
+	// Records with larger fields fail the import.
+	MaxFieldSize int
+}
+
+// ExportVersion is the version of the export layout written by ExportItems.
+// Exports written before versioning was introduced have no version and are read as version 1.
+const ExportVersion = 1
+
+// ErrExportVersion is returned, wrapped with the version, when importing an export written by a newer version of mbuckets
+var ErrExportVersion = errors.New("Unsupported export version")
+
+// exportHeader is written as a single JSON line at the start of every export
+type exportHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version,omitempty"`
+}
+
+// record is a single entry in an export.
+//
+// Bucket holds the path of the nested bolt.Bucket relative to the exported one, as separate segments,
+// so that exports do not depend on the separator in use.
+// A record without a Key marks the existence of the bolt.Bucket at Bucket.
+type record struct {
+	Bucket [][]byte
+	Key    []byte
+	Value  []byte
+}
+
+type recordEncoder interface {
+	Encode(rec record) error
+	Flush() error
+}
+
+type recordDecoder interface {
+	// Decode returns io.EOF when there are no more records in the stream
+	Decode() (record, error)
+}
+
+// ExportItems writes all the key/value pairs in the bolt.Bucket specified by this Bucket, and in all buckets under it,
+// to `w` in the given format
+func (b *Bucket) ExportItems(w io.Writer, format ItemFormat) error {
+	buf := bufio.NewWriter(w)
+
+	encoder, err := newRecordEncoder(buf, format)
+	if err != nil {
+		return err
+	}
+
+	header, err := json.Marshal(exportHeader{format.String(), ExportVersion})
+	if err != nil {
+		return err
+	}
+
+	_, err = buf.Write(append(header, '\n'))
+	if err != nil {
+		return err
+	}
+
+	err = b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return exportBucket(bucket, nil, encoder)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return encoder.Flush()
+}
+
+func exportBucket(bucket *bolt.Bucket, path [][]byte, encoder recordEncoder) error {
+	return bucket.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return encoder.Encode(record{path, k, v})
+		}
+
+		subPath := make([][]byte, len(path)+1)
+		copy(subPath, path)
+		subPath[len(path)] = k
+
+		err := encoder.Encode(record{Bucket: subPath})
+		if err != nil {
+			return err
+		}
+
+		return exportBucket(bucket.Bucket(k), subPath, encoder)
+	})
+}
+
+// ImportItems reads an export in the given format from `r` and puts its contents in the bolt.Bucket specified by this Bucket.
+//
+// Nested buckets in the export are created under this Bucket, and existing keys are overwritten.
+// A stream of Items in the given format without the header written by ExportItems, as written by other producers,
+// is imported into this Bucket itself. Records are written in batches, one transaction per batch.
+// If an error occurs, the batches written before it remain in the bolt.Bucket.
+func (b *Bucket) ImportItems(r io.Reader, format ItemFormat) error {
+	_, err := b.ImportItemsWith(r, format, nil)
+	return err
+}
+
+// ImportItemsWith is ImportItems with given options, and returns the conflicts with existing keys encountered during the import.
+//
+// Progress is checkpointed with every batch. If the import fails after writing some batches, a *ResumableError
+// is returned, and its Token can be set as ImportOptions.Resume to import the rest of the same export.
+func (b *Bucket) ImportItemsWith(r io.Reader, format ItemFormat, options *ImportOptions) (conflicts []Conflict, err error) {
+	err = b.track(OpImport, nil, func() error {
+		conflicts, err = b.importItems(r, format, options)
+		return err
+	})
+
+	return conflicts, err
+}
+
+func (b *Bucket) importItems(r io.Reader, format ItemFormat, options *ImportOptions) ([]Conflict, error) {
+	if options == nil {
+		options = &ImportOptions{}
+	}
+
+	decoder, err := openExport(r, format, options.MaxFieldSize)
+	if err != nil {
+		return nil, err
+	}
+
+	cp, err := b.DB.loadCheckpoint(options.Resume, checkpointImport, b.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := uint64(0); idx < cp.Done; idx++ {
+		_, err = decoder.Decode()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to skip imported record %d: %s", idx, unexpectedEOF(err))
+		}
+	}
+
+	var conflicts []Conflict
+	records := make([]record, 0, importBatchSize)
+
+	for {
+		rec, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return conflicts, cp.resumable(err)
+		}
+
+		records = append(records, rec)
+		if len(records) == importBatchSize {
+			batchConflicts, err := b.importRecords(records, options, cp, false)
+			if err != nil {
+				return conflicts, cp.resumable(err)
+			}
+			conflicts = append(conflicts, batchConflicts...)
+			records = records[:0]
+		}
+	}
+
+	batchConflicts, err := b.importRecords(records, options, cp, true)
+	if err != nil {
+		return conflicts, cp.resumable(err)
+	}
+
+	return append(conflicts, batchConflicts...), nil
+}
+
+// importRecords writes a batch of records and advances the checkpoint in the same transaction,
+// or removes it when this is the final batch
+func (b *Bucket) importRecords(records []record, options *ImportOptions, cp *checkpoint, final bool) ([]Conflict, error) {
+	var created [][][]byte
+	var conflicts []Conflict
+
+	buckets := b.segments()
+	defer b.afterCommit(func() { b.DB.invalidateKeyIndexes(buckets) })
+
+	err := b.update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		created, conflicts = nil, nil
+
+		for _, rec := range records {
+			rec, keep, err := options.transform(b.Separator, rec)
+			if err != nil {
+				return err
+			}
+			if !keep {
+				continue
+			}
+
+			target := bucket
+			for idx, bucketName := range rec.Bucket {
+				subBucket := target.Bucket(bucketName)
+				if subBucket == nil {
+					var err error
+					subBucket, err = target.CreateBucket(bucketName)
+					if err != nil {
+						return err
+					}
+
+					created = append(created, rec.Bucket[:idx+1])
+				}
+
+				target = subBucket
+			}
+
+			if len(rec.Key) == 0 {
+				continue
+			}
+
+			value := rec.Value
+			if value == nil {
+				value = []byte{}
+			}
+
+			existing := target.Get(rec.Key)
+			if existing != nil {
+				conflict := Conflict{
+					Bucket:   b.join(rec.Bucket),
+					Key:      rec.Key,
+					Existing: make([]byte, len(existing)),
+					Incoming: value,
+				}
+				copy(conflict.Existing, existing)
+
+				resolved, err := options.resolve(b.Separator, conflict)
+				if err != nil {
+					return err
+				}
+
+				conflict.Replaced = resolved != nil
+				conflicts = append(conflicts, conflict)
+
+				if resolved == nil {
+					continue
+				}
+
+				if isWriteOnce(tx, append(b.segments(), rec.Bucket...)) {
+					return writeOnceError(conflict.Bucket, rec.Key)
+				}
+				value = resolved
+			}
+
+			err = target.Put(rec.Key, value)
+			if err != nil {
+				return err
+			}
+		}
+
+		if final {
+			return cp.finish(tx)
+		}
+
+		return cp.advance(tx, uint64(len(records)))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	b.afterCommit(func() {
+		for _, path := range created {
+			b.DB.structureChanged(append(b.segments(), path...), false)
+		}
+	})
+
+	return conflicts, nil
+}
+
+// join returns the complete name of the bucket at the given path relative to this Bucket
+func (b *Bucket) join(path [][]byte) []byte {
+	return bytes.Join(append([][]byte{b.Name}, path...), b.Separator)
+}
+
+// VerifyExport checks that `r` holds a complete and well formed export that can be imported with ImportItems
+func VerifyExport(r io.Reader) error {
+	decoder, err := openExport(r, -1, 0)
+	if err != nil {
+		return err
+	}
+
+	for idx := 0; ; idx++ {
+		rec, err := decoder.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Invalid export record %d: %s", idx, err)
+		}
+
+		for _, bucketName := range rec.Bucket {
+			if len(bucketName) == 0 || len(bucketName) > bolt.MaxKeySize {
+				return fmt.Errorf("Invalid export record %d: invalid bucket name length %d", idx, len(bucketName))
+			}
+		}
+
+		if len(rec.Key) == 0 {
+			if len(rec.Bucket) == 0 || len(rec.Value) != 0 {
+				return fmt.Errorf("Invalid export record %d: missing key", idx)
+			}
+			continue
+		}
+
+		if len(rec.Key) > bolt.MaxKeySize {
+			return fmt.Errorf("Invalid export record %d: key too large", idx)
+		}
+
+		if len(rec.Value) > bolt.MaxValueSize {
+			return fmt.Errorf("Invalid export record %d: value too large", idx)
+		}
+	}
+}
+
+// openExport reads the export header from `r` and returns a decoder for the records following it,
+// or a decoder for a stream of Items in the given format if there is no header. A negative format accepts any known format,
+// but requires a header. `maxField` bounds the size of the fields of binary records, see ImportOptions.MaxFieldSize.
+func openExport(r io.Reader, format ItemFormat, maxField int) (recordDecoder, error) {
+	buf := bufio.NewReader(r)
+
+	header, ok := readHeader(buf)
+	if !ok {
+		if format < 0 {
+			return nil, errors.New("Invalid export header: missing")
+		}
+		return newItemDecoder(buf, format, maxField)
+	}
+
+	if header.Version > ExportVersion {
+		return nil, fmt.Errorf("%w: %d, newest supported version is %d", ErrExportVersion, header.Version, ExportVersion)
+	}
+
+	headerFormat := ItemFormat(-1)
+	for f, name := range formatNames {
+		if name == header.Format {
+			headerFormat = f
+		}
+	}
+
+	if headerFormat < 0 {
+		return nil, fmt.Errorf("Unknown export format: %s", header.Format)
+	}
+
+	if format >= 0 && format != headerFormat {
+		return nil, fmt.Errorf("Export format mismatch: expected %s, found %s", format, headerFormat)
+	}
+
+	return newRecordDecoder(buf, headerFormat, maxField)
+}
+
+// readHeader reads the export header at the start of `buf`.
+// It reports false without consuming anything if the stream does not start with a JSON line holding an export format.
+func readHeader(buf *bufio.Reader) (header exportHeader, ok bool) {
+	// A short stream is returned along with an error, and is checked all the same
+	data, _ := buf.Peek(buf.Size())
+
+	end := bytes.IndexByte(data, '\n')
+	if end < 0 || json.Unmarshal(data[:end], &header) != nil || header.Format == "" {
+		return header, false
+	}
+
+	buf.Discard(end + 1)
+	return header, true
+}
+
+func newRecordEncoder(buf *bufio.Writer, format ItemFormat) (recordEncoder, error) {
+	switch format {
+	case FormatGob:
+		return &gobRecordEncoder{buf, gob.NewEncoder(buf)}, nil
+	case FormatNDJSON:
+		return &jsonRecordEncoder{buf, json.NewEncoder(buf)}, nil
+	case FormatBinary:
+		return &binaryRecordEncoder{buf}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown item format: %d", format)
+}
+
+func newRecordDecoder(buf *bufio.Reader, format ItemFormat, maxField int) (recordDecoder, error) {
+	switch format {
+	case FormatGob:
+		return &gobRecordDecoder{gob.NewDecoder(buf)}, nil
+	case FormatNDJSON:
+		return &jsonRecordDecoder{json.NewDecoder(buf)}, nil
+	case FormatBinary:
+		return newBinaryRecordDecoder(buf, maxField), nil
+	}
+
+	return nil, fmt.Errorf("Unknown item format: %d", format)
+}
+
+// newItemDecoder returns a decoder for a stream of Items without export header in the given format,
+// each Item being decoded into a record of the top level bucket
+func newItemDecoder(buf *bufio.Reader, format ItemFormat, maxField int) (recordDecoder, error) {
+	switch format {
+	case FormatGob:
+		decoder := gob.NewDecoder(buf)
+		return &itemDecoder{func(item *Item) error { return decoder.Decode(item) }}, nil
+	case FormatNDJSON:
+		decoder := json.NewDecoder(buf)
+		return &itemDecoder{func(item *Item) error { return decoder.Decode(item) }}, nil
+	case FormatBinary:
+		return &itemDecoder{newBinaryRecordDecoder(buf, maxField).decodeItem}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown item format: %d", format)
+}
+
+// itemDecoder decodes a stream of Items with function `decode`
+type itemDecoder struct {
+	decode func(*Item) error
+}
+
+func (d *itemDecoder) Decode() (rec record, err error) {
+	var item Item
+	err = d.decode(&item)
+	return record{Key: item.Key, Value: item.Value}, err
+}
+
+type gobRecordEncoder struct {
+	*bufio.Writer
+	encoder *gob.Encoder
+}
+
+func (e *gobRecordEncoder) Encode(rec record) error {
+	return e.encoder.Encode(rec)
+}
+
+type gobRecordDecoder struct {
+	decoder *gob.Decoder
+}
+
+func (d *gobRecordDecoder) Decode() (rec record, err error) {
+	err = d.decoder.Decode(&rec)
+	return rec, err
+}
+
+// jsonBytes is a byte slice encoded as a JSON string when it is valid UTF-8,
+// and as an object holding its base64 encoding otherwise
+type jsonBytes []byte
+
+type jsonBase64 struct {
+	Base64 string `json:"base64"`
+}
+
+func (j jsonBytes) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(j) {
+		return json.Marshal(string(j))
+	}
+
+	return json.Marshal(jsonBase64{base64.StdEncoding.EncodeToString(j)})
+}
+
+func (j *jsonBytes) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var s string
+		err := json.Unmarshal(data, &s)
+		*j = jsonBytes(s)
+		return err
+	}
+
+	var encoded jsonBase64
+	err := json.Unmarshal(data, &encoded)
+	if err != nil {
+		return err
+	}
+
+	*j, err = base64.StdEncoding.DecodeString(encoded.Base64)
+	return err
+}
+
+type jsonRecord struct {
+	Bucket []jsonBytes `json:"bucket,omitempty"`
+	Key    jsonBytes   `json:"key,omitempty"`
+	Value  jsonBytes   `json:"value,omitempty"`
+}
+
+type jsonRecordEncoder struct {
+	*bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e *jsonRecordEncoder) Encode(rec record) error {
+	jsonRec := jsonRecord{Key: rec.Key, Value: rec.Value}
+	for _, bucketName := range rec.Bucket {
+		jsonRec.Bucket = append(jsonRec.Bucket, bucketName)
+	}
+
+	return e.encoder.Encode(jsonRec)
+}
+
+type jsonRecordDecoder struct {
+	decoder *json.Decoder
+}
+
+func (d *jsonRecordDecoder) Decode() (rec record, err error) {
+	var jsonRec jsonRecord
+	err = d.decoder.Decode(&jsonRec)
+	if err != nil {
+		return rec, err
+	}
+
+	rec.Key, rec.Value = jsonRec.Key, jsonRec.Value
+	for _, bucketName := range jsonRec.Bucket {
+		rec.Bucket = append(rec.Bucket, bucketName)
+	}
+
+	return rec, nil
+}
+
+type binaryRecordEncoder struct {
+	*bufio.Writer
+}
+
+func (e *binaryRecordEncoder) Encode(rec record) error {
+	err := e.writeUvarint(uint64(len(rec.Bucket)))
+	if err != nil {
+		return err
+	}
+
+	fields := append(append([][]byte{}, rec.Bucket...), rec.Key, rec.Value)
+	for _, field := range fields {
+		err = e.writeUvarint(uint64(len(field)))
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Write(field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *binaryRecordEncoder) writeUvarint(x uint64) error {
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], x)
+
+	_, err := e.Write(size[:n])
+	return err
+}
+
+// Fields of binary records up to this size are allocated upfront
+const fieldChunkSize = 64 * 1024
+
+type binaryRecordDecoder struct {
+	reader *bufio.Reader
+
+	// Maximum size of a field
+	maxField uint64
+}
+
+// newBinaryRecordDecoder returns a binaryRecordDecoder reading fields of at most `maxField` bytes, zero for bolt.MaxValueSize
+func newBinaryRecordDecoder(buf *bufio.Reader, maxField int) *binaryRecordDecoder {
+	if maxField <= 0 {
+		maxField = bolt.MaxValueSize
+	}
+
+	return &binaryRecordDecoder{buf, uint64(maxField)}
+}
+
+func (d *binaryRecordDecoder) Decode() (rec record, err error) {
+	numBuckets, err := binary.ReadUvarint(d.reader)
+	if err != nil {
+		return rec, err
+	}
+
+	if numBuckets > bolt.MaxKeySize {
+		return rec, fmt.Errorf("Too many nested buckets: %d", numBuckets)
+	}
+
+	for idx := uint64(0); idx < numBuckets; idx++ {
+		bucketName, err := d.readField()
+		if err != nil {
+			return rec, unexpectedEOF(err)
+		}
+		rec.Bucket = append(rec.Bucket, bucketName)
+	}
+
+	rec.Key, err = d.readField()
+	if err != nil {
+		return rec, unexpectedEOF(err)
+	}
+
+	rec.Value, err = d.readField()
+	return rec, unexpectedEOF(err)
+}
+
+// decodeItem reads an Item written as a length prefixed key followed by a length prefixed value
+func (d *binaryRecordDecoder) decodeItem(item *Item) (err error) {
+	item.Key, err = d.readField()
+	if err != nil {
+		return err
+	}
+
+	item.Value, err = d.readField()
+	return unexpectedEOF(err)
+}
+
+func (d *binaryRecordDecoder) readField() ([]byte, error) {
+	size, err := binary.ReadUvarint(d.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > d.maxField {
+		return nil, fmt.Errorf("Record field too large: %d bytes", size)
+	}
+
+	// The size is untrusted, so the field grows with the bytes actually read rather than being allocated upfront
+	var field bytes.Buffer
+	if size <= fieldChunkSize {
+		field.Grow(int(size))
+	}
+
+	_, err = io.CopyN(&field, d.reader, int64(size))
+	return field.Bytes(), err
+}
+
+// unexpectedEOF converts io.EOF into io.ErrUnexpectedEOF for errors in the middle of a record
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
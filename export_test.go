@@ -0,0 +1,392 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+var itemFormats = map[string]mbuckets.ItemFormat{
+	"gob":    mbuckets.FormatGob,
+	"ndjson": mbuckets.FormatNDJSON,
+	"binary": mbuckets.FormatBinary,
+}
+
+func TestExportImportItems(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	srcBucketName := []byte("Source")
+	t.Logf("Creating bucket: %s", srcBucketName)
+	srcBucket := db.Bucket(srcBucketName)
+
+	items := make([]mbuckets.Item, 0, 1500)
+	for i := 0; i < 1500; i++ {
+		items = append(items, mbuckets.Item{[]byte(fmt.Sprintf("key%04d", i)), []byte{byte(i), 0, 0xff}})
+	}
+
+	t.Logf("Inserting %d items", len(items))
+	err = srcBucket.InsertAll(items)
+	if err != nil {
+		t.Errorf("Unable to insert items in bucket. Error: %s", err.Error())
+	}
+
+	for name, format := range itemFormats {
+		var buf bytes.Buffer
+
+		t.Logf("Exporting items in %s format", name)
+		err = srcBucket.ExportItems(&buf, format)
+		if err != nil {
+			t.Errorf("Unable to export items from bucket. Error: %s", err.Error())
+		}
+
+		dstBucket := db.BucketString("Destination/" + name)
+
+		t.Logf("Importing items in %s format into bucket: %s", name, dstBucket.Name)
+		err = dstBucket.ImportItems(&buf, format)
+		if err != nil {
+			t.Errorf("Unable to import items in bucket. Error: %s", err.Error())
+		}
+
+		results, err := dstBucket.GetAll()
+		if err != nil {
+			t.Errorf("Unable to retrieve items from bucket. Error: %s", err.Error())
+		}
+
+		if len(results) != len(items) {
+			t.Errorf("Number of items imported in %s format does not match the number of items exported", name)
+			continue
+		}
+
+		for idx, result := range results {
+			if !bytes.Equal(result.Key, items[idx].Key) || !bytes.Equal(result.Value, items[idx].Value) {
+				t.Errorf("Item imported in %s format does not match the item exported", name)
+				break
+			}
+		}
+	}
+}
+
+// dumpTree returns the contents of the given bucket and all buckets under it, keyed by path relative to it
+func dumpTree(db *TestDB, bucket *mbuckets.Bucket) (map[string]string, error) {
+	tree := make(map[string]string)
+
+	bucketNames, err := bucket.GetAllBucketNames()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bucketName := range append([][]byte{bucket.Name}, bucketNames...) {
+		relativeName := strings.TrimPrefix(string(bucketName), string(bucket.Name))
+		tree[relativeName] = ""
+
+		items, err := db.Bucket(bucketName).WithSeparator(bucket.Separator).GetAll()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			tree[fmt.Sprintf("%s %q", relativeName, item.Key)] = string(item.Value)
+		}
+	}
+
+	return tree, nil
+}
+
+func randomBytes(rnd *rand.Rand, min, max int) []byte {
+	buf := make([]byte, min+rnd.Intn(max-min+1))
+	rnd.Read(buf)
+	return buf
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	run := 0
+	separators := [][]byte{[]byte("/"), []byte(":"), []byte("::")}
+
+	roundTrip := func(seed int64) bool {
+		rnd := rand.New(rand.NewSource(seed))
+		separator := separators[rnd.Intn(len(separators))]
+		run++
+
+		srcName := []byte(fmt.Sprintf("Source%d", run))
+		srcBucket := db.Bucket(srcName).WithSeparator(separator)
+
+		err := srcBucket.CreateBucket()
+		if err != nil {
+			t.Errorf("Unable to create bucket. Error: %s", err.Error())
+			return false
+		}
+
+		for i := 0; i < rnd.Intn(8); i++ {
+			name := bytes.NewBuffer(append([]byte{}, srcName...))
+			for depth := 0; depth <= rnd.Intn(3); depth++ {
+				name.Write(separator)
+				name.WriteString(fmt.Sprintf("Bucket%d", rnd.Intn(3)))
+			}
+
+			bucket := db.Bucket(name.Bytes()).WithSeparator(separator)
+			for j := 0; j < rnd.Intn(5); j++ {
+				err = bucket.Insert(randomBytes(rnd, 1, 16), randomBytes(rnd, 0, 32))
+				if err != nil {
+					t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+					return false
+				}
+			}
+
+			err = bucket.CreateBucket()
+			if err != nil {
+				t.Errorf("Unable to create bucket. Error: %s", err.Error())
+				return false
+			}
+		}
+
+		expected, err := dumpTree(db, srcBucket)
+		if err != nil {
+			t.Errorf("Unable to read bucket tree. Error: %s", err.Error())
+			return false
+		}
+
+		for name, format := range itemFormats {
+			var buf bytes.Buffer
+			err = srcBucket.ExportItems(&buf, format)
+			if err != nil {
+				t.Errorf("Unable to export items from bucket. Error: %s", err.Error())
+				return false
+			}
+
+			err = mbuckets.VerifyExport(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Errorf("Export in %s format failed verification. Error: %s", name, err.Error())
+				return false
+			}
+
+			dstBucket := db.Bucket([]byte(fmt.Sprintf("Destination%d%s%s", run, separator, name))).WithSeparator(separator)
+			err = dstBucket.ImportItems(&buf, format)
+			if err != nil {
+				t.Errorf("Unable to import items in bucket. Error: %s", err.Error())
+				return false
+			}
+
+			imported, err := dumpTree(db, dstBucket)
+			if err != nil {
+				t.Errorf("Unable to read bucket tree. Error: %s", err.Error())
+				return false
+			}
+
+			if fmt.Sprint(imported) != fmt.Sprint(expected) {
+				t.Errorf("Bucket tree imported in %s format does not match the exported one", name)
+				return false
+			}
+		}
+
+		return true
+	}
+
+	err = quick.Check(roundTrip, &quick.Config{MaxCount: 25})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyExport(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	err = bucket.ExportItems(&buf, mbuckets.FormatBinary)
+	if err != nil {
+		t.Errorf("Unable to export items from bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Verifying a truncated export")
+	err = mbuckets.VerifyExport(bytes.NewReader(buf.Bytes()[:buf.Len()-2]))
+	if err == nil {
+		t.Error("Truncated export passed verification")
+	}
+
+	t.Log("Verifying an export without header")
+	err = mbuckets.VerifyExport(strings.NewReader(`{"key":"key1","value":"value1"}`))
+	if err == nil {
+		t.Error("Export without header passed verification")
+	}
+}
+
+func TestExportVersion(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	err = bucket.ExportItems(&buf, mbuckets.FormatNDJSON)
+	if err != nil {
+		t.Errorf("Unable to export items from bucket. Error: %s", err.Error())
+	}
+
+	export := buf.String()
+	header := export[:strings.IndexByte(export, '\n')]
+	t.Logf("Export header: %s", header)
+
+	if header != fmt.Sprintf(`{"format":"ndjson","version":%d}`, mbuckets.ExportVersion) {
+		t.Errorf("Export header %s does not hold the export version", header)
+	}
+
+	t.Log("Importing an export without version")
+	legacy := `{"format":"ndjson"}` + export[len(header):]
+	err = db.BucketString("Legacy").ImportItems(strings.NewReader(legacy), mbuckets.FormatNDJSON)
+	if err != nil {
+		t.Errorf("Unable to import export without version. Error: %s", err.Error())
+	}
+
+	value, err := db.BucketString("Legacy").GetString("key1")
+	if err != nil || value != "value1" {
+		t.Errorf("Value %s imported from export without version does not match the exported value. Error: %v", value, err)
+	}
+
+	t.Log("Importing an export with a newer version")
+	newer := fmt.Sprintf(`{"format":"ndjson","version":%d}`, mbuckets.ExportVersion+1) + export[len(header):]
+	err = db.BucketString("Newer").ImportItems(strings.NewReader(newer), mbuckets.FormatNDJSON)
+	if !errors.Is(err, mbuckets.ErrExportVersion) {
+		t.Errorf("Import of a newer export did not fail with ErrExportVersion. Error: %v", err)
+	}
+
+	err = mbuckets.VerifyExport(strings.NewReader(newer))
+	if !errors.Is(err, mbuckets.ErrExportVersion) {
+		t.Errorf("Verification of a newer export did not fail with ErrExportVersion. Error: %v", err)
+	}
+}
+
+func TestImportBinaryFieldSize(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	header := "{\"format\":\"binary\",\"version\":1}\n"
+	bucket := db.BucketString("Import")
+
+	t.Log("Importing a record declaring a huge key followed by a few bytes")
+	huge := binary.AppendUvarint([]byte{0}, 1<<30)
+	_, err = bucket.ImportItemsWith(strings.NewReader(header+string(huge)+"key"), mbuckets.FormatBinary, nil)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Import of a truncated huge field did not fail with io.ErrUnexpectedEOF. Error: %v", err)
+	}
+
+	t.Log("Importing a record with a key larger than MaxFieldSize")
+	rec := binary.AppendUvarint([]byte{0}, 20)
+	rec = append(rec, strings.Repeat("k", 20)...)
+	rec = append(binary.AppendUvarint(rec, 5), "value"...)
+
+	_, err = bucket.ImportItemsWith(strings.NewReader(header+string(rec)), mbuckets.FormatBinary, &mbuckets.ImportOptions{MaxFieldSize: 10})
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Import of a field larger than MaxFieldSize did not fail. Error: %v", err)
+	}
+
+	t.Log("Importing the same record within MaxFieldSize")
+	_, err = bucket.ImportItemsWith(strings.NewReader(header+string(rec)), mbuckets.FormatBinary, &mbuckets.ImportOptions{MaxFieldSize: 20})
+	if err != nil {
+		t.Errorf("Unable to import record within MaxFieldSize. Error: %s", err.Error())
+	}
+
+	value, err := bucket.GetString(strings.Repeat("k", 20))
+	if err != nil || value != "value" {
+		t.Errorf("Imported value %q does not match the record. Error: %v", value, err)
+	}
+}
+
+func TestImportItemStreams(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	items := []mbuckets.Item{{Key: []byte("key1"), Value: []byte("value1")}, {Key: []byte("key2"), Value: []byte{0xff, 0x00}}}
+
+	for name, format := range itemFormats {
+		t.Logf("Importing a stream of Items without header in %s format", name)
+
+		var buf bytes.Buffer
+		switch format {
+		case mbuckets.FormatGob:
+			encoder := gob.NewEncoder(&buf)
+			for _, item := range items {
+				encoder.Encode(item)
+			}
+		case mbuckets.FormatNDJSON:
+			encoder := json.NewEncoder(&buf)
+			for _, item := range items {
+				encoder.Encode(item)
+			}
+		case mbuckets.FormatBinary:
+			for _, item := range items {
+				buf.Write(binary.AppendUvarint(nil, uint64(len(item.Key))))
+				buf.Write(item.Key)
+				buf.Write(binary.AppendUvarint(nil, uint64(len(item.Value))))
+				buf.Write(item.Value)
+			}
+		}
+
+		bucket := db.BucketString("Stream/" + name)
+		err = bucket.ImportItems(&buf, format)
+		if err != nil {
+			t.Errorf("Unable to import stream of Items in %s format. Error: %s", name, err.Error())
+			continue
+		}
+
+		for _, item := range items {
+			value, err := bucket.Get(item.Key)
+			if err != nil || !bytes.Equal(value, item.Value) {
+				t.Errorf("Imported value %q of key %s does not match the stream. Error: %v", value, item.Key, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package mbuckets
+
+// Failpoints injects errors into the operations of a DB, to test how an application recovers from storage failures.
+// A nil function never fails.
+type Failpoints struct {
+	// Called before committing every write transaction, an error returned rolls the transaction back
+	Commit func(bucket []byte) error
+
+	// Called after committing every write transaction, an error returned is returned to the caller
+	// although the transaction remains committed, as when syncing the database file fails after writing it
+	Sync func(bucket []byte) error
+
+	// Called before every key/value pair visited by Map, MapPrefix, MapRange and their variants,
+	// an error returned stops the iteration
+	Iterate func(bucket, key []byte) error
+}
+
+// SetFailpoints sets the Failpoints of this DB. Passing an empty Failpoints turns failure injection off.
+//
+// Failpoints are meant for tests only.
+func (db *DB) SetFailpoints(failpoints Failpoints) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.failpoints = failpoints
+}
+
+// iterateFailpoint wraps iteration function `fn` on the bolt.Bucket with the given name with the Iterate failpoint
+func (db *DB) iterateFailpoint(name []byte, fn func([]byte, []byte) error) func([]byte, []byte) error {
+	db.mu.RLock()
+	failpoint := db.failpoints.Iterate
+	db.mu.RUnlock()
+
+	if failpoint == nil {
+		return fn
+	}
+
+	return func(k, v []byte) error {
+		if err := failpoint(name, k); err != nil {
+			return err
+		}
+		return fn(k, v)
+	}
+}
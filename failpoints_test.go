@@ -0,0 +1,77 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestFailpoints(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+	err = bucket.InsertAllString(map[string]string{"key1": "value1", "key2": "value2", "key3": "value3"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	injected := errors.New("injected")
+
+	t.Log("Injecting a commit failure")
+	db.SetFailpoints(mbuckets.Failpoints{Commit: func([]byte) error { return injected }})
+
+	err = bucket.InsertString("key4", "value4")
+	if err != injected {
+		t.Errorf("Insert did not return the injected commit failure. Error: %v", err)
+	}
+
+	exists, _ := bucket.ExistsString("key4")
+	if exists {
+		t.Error("Transaction with injected commit failure was committed")
+	}
+
+	t.Log("Injecting a sync failure")
+	db.SetFailpoints(mbuckets.Failpoints{Sync: func([]byte) error { return injected }})
+
+	err = bucket.InsertString("key4", "value4")
+	if err != injected {
+		t.Errorf("Insert did not return the injected sync failure. Error: %v", err)
+	}
+
+	exists, _ = bucket.ExistsString("key4")
+	if !exists {
+		t.Error("Transaction with injected sync failure was not committed")
+	}
+
+	t.Log("Injecting an iteration failure at key: key3")
+	db.SetFailpoints(mbuckets.Failpoints{Iterate: func(_, key []byte) error {
+		if string(key) == "key3" {
+			return injected
+		}
+		return nil
+	}})
+
+	items, err := bucket.GetAll()
+	if err != injected {
+		t.Errorf("GetAll did not return the injected iteration failure. Error: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Errorf("Iteration was not stopped at the injected failure, %d items visited", len(items))
+	}
+
+	t.Log("Turning failure injection off")
+	db.SetFailpoints(mbuckets.Failpoints{})
+
+	items, err = bucket.GetAll()
+	if err != nil || len(items) != 4 {
+		t.Errorf("Iteration failed after turning failure injection off. Error: %v", err)
+	}
+}
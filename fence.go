@@ -0,0 +1,139 @@
+package mbuckets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+// ErrStaleFence is returned, wrapped with the tokens, when a fencing token is lower than the fence of a bolt.Bucket
+var ErrStaleFence = errors.New("Stale fencing token")
+
+// Name of the meta bucket holding the fences of bolt.Buckets
+var fencesBucketName = []byte("fences")
+
+// SetFence raises the fence of the bolt.Bucket specified by this Bucket to the given token.
+// Writes through a Bucket carrying a lower token, see WithFence, fail with an error wrapping ErrStaleFence afterwards.
+//
+// The fence can only be raised, setting a token lower than the current fence fails with an error wrapping ErrStaleFence.
+// It can be set before the bolt.Bucket is created, and is removed when the bolt.Bucket is deleted through DeleteBucket.
+func (b *Bucket) SetFence(token uint64) error {
+	key := pathKey(b.segments())
+
+	return b.write(func(tx *bolt.Tx) error {
+		fences, err := metaBucket(tx, fencesBucketName)
+		if err != nil {
+			return err
+		}
+
+		if current := readFence(fences, key); token < current {
+			return staleFence(b.Name, token, current)
+		}
+
+		return writeFence(fences, key, token)
+	})
+}
+
+// Fence returns the fence of the bolt.Bucket specified by this Bucket, zero if none has been set
+func (b *Bucket) Fence() (fence uint64, err error) {
+	key := pathKey(b.segments())
+
+	err = b.read(func(tx *bolt.Tx) error {
+		fences, err := metaBucket(tx, fencesBucketName)
+		if err != nil || fences == nil {
+			return err
+		}
+
+		fence = readFence(fences, key)
+		return nil
+	})
+
+	return fence, err
+}
+
+// WithFence sets the fencing token carried by all writes through this Bucket and returns a pointer to this Bucket.
+//
+// A write fails with an error wrapping ErrStaleFence if the token is lower than the fence of the bolt.Bucket,
+// and raises the fence to the token otherwise, so that writers holding an older token are rejected from then on.
+func (b *Bucket) WithFence(token uint64) *Bucket {
+	b.fence = token
+	b.fenced = true
+	return b
+}
+
+// checkFence verifies the fencing token of this Bucket against the fence of the bolt.Bucket with the given path
+func (b *Bucket) checkFence(tx *bolt.Tx, buckets [][]byte) error {
+	if !b.fenced {
+		return nil
+	}
+
+	fences, err := metaBucket(tx, fencesBucketName)
+	if err != nil {
+		return err
+	}
+
+	key := pathKey(buckets)
+	current := readFence(fences, key)
+
+	if b.fence < current {
+		return staleFence(b.Name, b.fence, current)
+	}
+
+	if b.fence == current {
+		return nil
+	}
+
+	return writeFence(fences, key, b.fence)
+}
+
+// clearFences removes the fences of the bolt.Bucket with the given path and of all bolt.Buckets under it
+func clearFences(tx *bolt.Tx, buckets [][]byte) error {
+	meta := tx.Bucket(metaBucketName)
+	if meta == nil {
+		return nil
+	}
+
+	fences := meta.Bucket(fencesBucketName)
+	if fences == nil {
+		return nil
+	}
+
+	var keys [][]byte
+	prefix := []byte(pathKey(buckets))
+
+	cursor := fences.Cursor()
+	for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+		keys = append(keys, copyKey(k))
+	}
+
+	for _, key := range keys {
+		err := fences.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readFence(fences *bolt.Bucket, key string) uint64 {
+	v := fences.Get([]byte(key))
+	if len(v) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(v)
+}
+
+func writeFence(fences *bolt.Bucket, key string, token uint64) error {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, token)
+	return fences.Put([]byte(key), v)
+}
+
+func staleFence(name []byte, token, fence uint64) error {
+	return fmt.Errorf("%w for bucket %s: %d < %d", ErrStaleFence, name, token, fence)
+}
@@ -0,0 +1,59 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestFence(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Setting fence of bucket: Bucket1 to 5")
+	err = db.BucketString("Bucket1").SetFence(5)
+	if err != nil {
+		t.Errorf("Unable to set fence of bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Writing with stale token: 4")
+	err = db.BucketString("Bucket1").WithFence(4).InsertString("key1", "value1")
+	if !errors.Is(err, mbuckets.ErrStaleFence) {
+		t.Errorf("Write with stale token did not return ErrStaleFence. Error: %v", err)
+	}
+
+	t.Log("Writing with newer token: 6")
+	err = db.BucketString("Bucket1").WithFence(6).InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to write with newer token. Error: %s", err.Error())
+	}
+
+	fence, err := db.BucketString("Bucket1").Fence()
+	if err != nil || fence != 6 {
+		t.Errorf("Fence %d was not raised by write with newer token. Error: %v", fence, err)
+	}
+
+	t.Log("Writing with the previous current token: 5")
+	err = db.BucketString("Bucket1").WithFence(5).InsertString("key2", "value2")
+	if !errors.Is(err, mbuckets.ErrStaleFence) {
+		t.Errorf("Write with token older than a later write did not return ErrStaleFence. Error: %v", err)
+	}
+
+	t.Log("Lowering the fence")
+	err = db.BucketString("Bucket1").SetFence(1)
+	if !errors.Is(err, mbuckets.ErrStaleFence) {
+		t.Errorf("Lowering the fence did not return ErrStaleFence. Error: %v", err)
+	}
+
+	t.Log("Writing without a token")
+	err = db.BucketString("Bucket1").InsertString("key2", "value2")
+	if err != nil {
+		t.Errorf("Unable to write without a token. Error: %s", err.Error())
+	}
+}
@@ -0,0 +1,86 @@
+package mbuckets
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// Generation returns the generation counter of the whole bucket hierarchy in this DB.
+//
+// The counter is incremented whenever a bucket is created or deleted through mbuckets,
+// and after every function passed to Update, Batch or Bucket.Update, as these may create or delete buckets.
+func (db *DB) Generation() uint64 {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.generations[pathKey(nil)]
+}
+
+// Generation returns the generation counter of the subtree rooted at the bolt.Bucket specified by this Bucket.
+//
+// The counter is incremented whenever this bucket or a bucket under it is created or deleted through mbuckets,
+// so comparing two generations is a cheap way to detect that something under this path has changed.
+// Functions passed to Update, Batch and Bucket.Update increment the counters of all the buckets they may have changed.
+// Changes made directly through the embedded bolt.DB, or through the embedded bolt.Tx of a Tx, are not tracked.
+func (b *Bucket) Generation() uint64 {
+	b.DB.mu.RLock()
+	defer b.DB.mu.RUnlock()
+
+	return b.DB.generations[pathKey(b.segments())]
+}
+
+// structureChanged bumps the generations of the given bucket path and all its ancestors,
+// and of all its descendants when `subtree` is set because the bucket was deleted or anything under it may have changed.
+// Cached bucket names are dropped.
+func (db *DB) structureChanged(buckets [][]byte, subtree bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.generations == nil {
+		db.generations = make(map[string]uint64)
+	}
+
+	for idx := 0; idx <= len(buckets); idx++ {
+		db.generations[pathKey(buckets[:idx])]++
+	}
+
+	if subtree {
+		key := pathKey(buckets)
+		for path := range db.generations {
+			if path != key && strings.HasPrefix(path, key) {
+				db.generations[path]++
+			}
+		}
+	}
+
+	if db.bucketNameCache != nil {
+		db.bucketNameCache = make(map[string][][]byte)
+	}
+}
+
+// pathKey encodes bucket path segments as a string in which the key of a path is a prefix of the keys of all its descendants
+func pathKey(buckets [][]byte) string {
+	var key []byte
+	for _, bucket := range buckets {
+		key = binary.AppendUvarint(key, uint64(len(bucket)))
+		key = append(key, bucket...)
+	}
+
+	return string(key)
+}
+
+// splitPathKey decodes the bucket path segments encoded by pathKey, and reports false if the key is malformed
+func splitPathKey(key []byte) ([][]byte, bool) {
+	var buckets [][]byte
+	for len(key) > 0 {
+		size, n := binary.Uvarint(key)
+		if n <= 0 || uint64(len(key)-n) < size {
+			return nil, false
+		}
+
+		buckets = append(buckets, key[n:n+int(size)])
+		key = key[n+int(size):]
+	}
+
+	return buckets, true
+}
@@ -0,0 +1,68 @@
+package mbuckets_test
+
+import (
+	"testing"
+)
+
+func TestGeneration(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket1 := db.BucketString("Bucket1")
+	bucket2 := db.BucketString("Bucket1/Bucket2")
+	bucket3 := db.BucketString("Bucket3")
+
+	t.Logf("Creating Bucket: %s", bucket2.Name)
+	err = bucket2.CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	generation1 := bucket1.Generation()
+	generation2 := bucket2.Generation()
+	generation3 := bucket3.Generation()
+	t.Logf("Generations after creation: %d, %d, %d", generation1, generation2, generation3)
+
+	if generation1 == 0 || generation2 == 0 {
+		t.Error("Generation was not bumped on creation of bucket")
+	}
+
+	t.Logf("Inserting a key/value pair in existing Bucket: %s", bucket2.Name)
+	err = bucket2.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	if bucket1.Generation() != generation1 || bucket2.Generation() != generation2 {
+		t.Error("Generation was bumped without a structural change")
+	}
+
+	t.Logf("Creating Bucket: %s", bucket3.Name)
+	err = bucket3.CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	if bucket1.Generation() != generation1 {
+		t.Error("Generation was bumped by a change outside of its subtree")
+	}
+
+	if bucket3.Generation() == generation3 {
+		t.Error("Generation was not bumped on creation of bucket")
+	}
+
+	t.Logf("Deleting Bucket: %s", bucket1.Name)
+	err = bucket1.DeleteBucket()
+	if err != nil {
+		t.Errorf("Unable to delete bucket. Error: %s", err.Error())
+	}
+
+	if bucket1.Generation() == generation1 || bucket2.Generation() == generation2 {
+		t.Error("Generation was not bumped on deletion of bucket")
+	}
+}
@@ -0,0 +1,95 @@
+package mbuckets
+
+// Mutation describes a mutating call made on a Bucket, reported to the hooks set by OnCommit and OnRollback
+type Mutation struct {
+	// One of the Op constants
+	Op string
+
+	// Complete hierarchial name of the Bucket the call was made on
+	Bucket []byte
+
+	// The key of calls about a single key, nil otherwise
+	Key []byte
+}
+
+// OnCommit sets function `fn` to be called with the Mutations of every write transaction made through the Buckets of this DB,
+// once it has been committed. Passing nil stops reporting.
+//
+// Calls on a Bucket not bound to a Tx are reported one by one, while the calls on the Buckets of a Tx
+// are reported together when the Tx commits. Function `fn` is called on the goroutine that made the call.
+func (db *DB) OnCommit(fn func([]Mutation)) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.commitHook = fn
+}
+
+// OnRollback sets function `fn` to be called with the Mutations of every write transaction made through the Buckets of this DB
+// that failed and was rolled back, and the error it failed with. Passing nil stops reporting.
+//
+// For a Tx, the Mutations are the calls that succeeded before the Tx was rolled back.
+func (db *DB) OnRollback(fn func([]Mutation, error)) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.rollbackHook = fn
+}
+
+// OnCommit adds function `fn` to be called with the Mutations made through the Buckets of this Tx once it has been committed
+func (tx *Tx) OnCommit(fn func([]Mutation)) {
+	tx.commitHooks = append(tx.commitHooks, fn)
+}
+
+// OnRollback adds function `fn` to be called with the Mutations made through the Buckets of this Tx
+// and the error it failed with, if it is rolled back
+func (tx *Tx) OnRollback(fn func([]Mutation, error)) {
+	tx.rollbackHooks = append(tx.rollbackHooks, fn)
+}
+
+// mutated reports the Mutation of a call on this Bucket that returned the given error,
+// and returns the error of the hook it was reported to, see DB.callHook.
+// A call failing only with a *HookError has been committed, and is reported as such.
+func (b *Bucket) mutated(mutation Mutation, err error) error {
+	succeeded := err == nil || isHookError(err)
+
+	if b.tx != nil {
+		if succeeded {
+			b.tx.mutations = append(b.tx.mutations, mutation)
+		}
+		return nil
+	}
+
+	if succeeded {
+		return b.DB.committed(b.Name, []Mutation{mutation})
+	}
+
+	return b.DB.rolledBack(b.Name, []Mutation{mutation}, err)
+}
+
+func (db *DB) committed(name []byte, mutations []Mutation) error {
+	db.mu.RLock()
+	hook := db.commitHook
+	db.mu.RUnlock()
+
+	if hook == nil {
+		return nil
+	}
+
+	return db.callHook(name, func() {
+		hook(mutations)
+	})
+}
+
+func (db *DB) rolledBack(name []byte, mutations []Mutation, err error) error {
+	db.mu.RLock()
+	hook := db.rollbackHook
+	db.mu.RUnlock()
+
+	if hook == nil {
+		return nil
+	}
+
+	return db.callHook(name, func() {
+		hook(mutations, err)
+	})
+}
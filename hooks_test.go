@@ -0,0 +1,105 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestCommitRollbackHooks(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	var committed [][]mbuckets.Mutation
+	var rolledBack [][]mbuckets.Mutation
+
+	db.OnCommit(func(mutations []mbuckets.Mutation) {
+		committed = append(committed, mutations)
+	})
+
+	db.OnRollback(func(mutations []mbuckets.Mutation, err error) {
+		rolledBack = append(rolledBack, mutations)
+	})
+
+	t.Log("Inserting a key/value pair")
+	err = db.BucketString("Bucket1").InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	if len(committed) != 1 || committed[0][0].Op != mbuckets.OpInsert || string(committed[0][0].Key) != "key1" {
+		t.Errorf("Committed mutations %v do not match the insert", committed)
+	}
+
+	t.Log("Deleting a missing bucket")
+	err = db.BucketString("Missing").DeleteBucket()
+	if err == nil {
+		t.Error("Deleting a missing bucket did not fail")
+	}
+
+	if len(rolledBack) != 1 || rolledBack[0][0].Op != mbuckets.OpDeleteBucket {
+		t.Errorf("Rolled back mutations %v do not match the failed call", rolledBack)
+	}
+
+	committed, rolledBack = nil, nil
+
+	var txCommitted []mbuckets.Mutation
+
+	t.Log("Inserting key/value pairs in a transaction")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		tx.OnCommit(func(mutations []mbuckets.Mutation) {
+			txCommitted = mutations
+		})
+
+		err := tx.BucketString("Bucket1").InsertString("key2", "value2")
+		if err != nil {
+			return err
+		}
+
+		return tx.BucketString("Bucket2").DeleteString("key1")
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update in transaction. Error: %s", err.Error())
+	}
+
+	if len(txCommitted) != 2 || len(committed) != 1 || len(committed[0]) != 2 {
+		t.Errorf("Committed mutations %v do not match the transaction", committed)
+	}
+
+	errFailed := errors.New("failed")
+	var txRolledBack []mbuckets.Mutation
+	var txErr error
+
+	t.Log("Rolling back a transaction")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		tx.OnRollback(func(mutations []mbuckets.Mutation, err error) {
+			txRolledBack, txErr = mutations, err
+		})
+
+		err := tx.BucketString("Bucket1").InsertString("key3", "value3")
+		if err != nil {
+			return err
+		}
+
+		return errFailed
+	})
+
+	if err != errFailed {
+		t.Errorf("Transaction did not return the error of the function. Error: %v", err)
+	}
+
+	if len(txRolledBack) != 1 || string(txRolledBack[0].Key) != "key3" || txErr != errFailed {
+		t.Errorf("Rolled back mutations %v do not match the transaction", txRolledBack)
+	}
+
+	if len(committed) != 1 || len(rolledBack) != 1 {
+		t.Errorf("DB hooks were not called once per transaction: %d commits, %d rollbacks", len(committed), len(rolledBack))
+	}
+}
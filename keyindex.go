@@ -0,0 +1,217 @@
+package mbuckets
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/boltdb/bolt"
+)
+
+// keyIndex holds the sorted keys of a bolt.Bucket in memory
+type keyIndex struct {
+	keys   [][]byte
+	loaded bool
+
+	// Incremented whenever the keys become stale, so that a load racing with a write is discarded
+	version uint64
+}
+
+// keyChange is a key inserted into or removed from the bolt.Bucket of a keyIndex by an uncommitted transaction
+type keyChange struct {
+	index    *keyIndex
+	key      []byte
+	inserted bool
+}
+
+// EnableKeyIndex keeps the keys of the bolt.Bucket specified by this Bucket in memory,
+// to answer Exists, Count, CountPrefix, GetAllKeys, GetKeysPrefix, Floor and Ceiling without reading the bolt.Bucket.
+//
+// The keys are loaded on first use, and kept up to date with the keys inserted and removed through the Buckets of this DB
+// once their transactions commit. Functions passed to Update, Batch and Bucket.Update, as well as ImportItems,
+// make the keys stale instead, so that they are reloaded on next use. Writes made directly through the embedded bolt.DB,
+// or through the embedded bolt.Tx of a Tx, are not tracked.
+func (b *Bucket) EnableKeyIndex() {
+	b.DB.mu.Lock()
+	defer b.DB.mu.Unlock()
+
+	if b.DB.keyIndexes == nil {
+		b.DB.keyIndexes = make(map[string]*keyIndex)
+	}
+
+	key := pathKey(b.segments())
+	if _, ok := b.DB.keyIndexes[key]; !ok {
+		b.DB.keyIndexes[key] = &keyIndex{}
+	}
+}
+
+// DisableKeyIndex drops the in-memory keys of the bolt.Bucket specified by this Bucket
+func (b *Bucket) DisableKeyIndex() {
+	b.DB.mu.Lock()
+	defer b.DB.mu.Unlock()
+
+	delete(b.DB.keyIndexes, pathKey(b.segments()))
+}
+
+// invalidateKeyIndexes marks the key indexes of the given bucket path and all paths under it as stale,
+// or of all buckets if the path is empty
+func (db *DB) invalidateKeyIndexes(buckets [][]byte) {
+	db.mu.RLock()
+	enabled := len(db.keyIndexes) > 0
+	db.mu.RUnlock()
+
+	if !enabled {
+		return
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	key := pathKey(buckets)
+	for path, index := range db.keyIndexes {
+		if strings.HasPrefix(path, key) {
+			index.keys = nil
+			index.loaded = false
+			index.version++
+		}
+	}
+}
+
+// withIndexedKeys calls function `fn` with the in-memory keys of the bolt.Bucket specified by this Bucket, loading them if needed.
+// It reports false without calling function `fn` if the key index is disabled or could not be loaded, or if this Bucket
+// is bound to a Tx whose uncommitted writes the index does not reflect.
+//
+// Function `fn` is called with the lock of the DB held, so it must not modify the keys nor call other methods of the DB.
+func (b *Bucket) withIndexedKeys(fn func(keys [][]byte)) bool {
+	if b.tx != nil {
+		return false
+	}
+
+	key := pathKey(b.segments())
+
+	b.DB.mu.RLock()
+	index, ok := b.DB.keyIndexes[key]
+	if ok && index.loaded {
+		fn(index.keys)
+		b.DB.mu.RUnlock()
+		return true
+	}
+
+	var version uint64
+	if ok {
+		version = index.version
+	}
+	b.DB.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	keys, err := b.scanKeys(nil)
+	if err != nil {
+		return false
+	}
+
+	b.DB.mu.Lock()
+	defer b.DB.mu.Unlock()
+
+	if current, ok := b.DB.keyIndexes[key]; ok && current == index && index.version == version {
+		index.keys = keys
+		index.loaded = true
+	}
+
+	fn(keys)
+	return true
+}
+
+// recordKeyChange remembers that the given key has been inserted into or removed from the bolt.Bucket with the given path
+// by transaction `tx`, if the bolt.Bucket has a key index. The changes are applied once bolt commits `tx`,
+// and must be dropped with finishKeyChanges if it is rolled back.
+func (db *DB) recordKeyChange(tx *bolt.Tx, buckets [][]byte, key []byte, inserted bool) {
+	db.mu.RLock()
+	index, ok := db.keyIndexes[pathKey(buckets)]
+	db.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.keyChanges == nil {
+		db.keyChanges = make(map[*bolt.Tx][]keyChange)
+	}
+
+	if _, ok := db.keyChanges[tx]; !ok {
+		tx.OnCommit(func() {
+			db.finishKeyChanges(tx, true)
+		})
+	}
+
+	db.keyChanges[tx] = append(db.keyChanges[tx], keyChange{index, copyKey(key), inserted})
+}
+
+// finishKeyChanges applies the key changes recorded for transaction `tx` to the key indexes if it has been committed,
+// and drops them otherwise. Committed changes are applied by bolt's commit handler of `tx`, so callers only use it
+// to drop the changes left after `tx` has ended, which is a no-op once they have been applied.
+func (db *DB) finishKeyChanges(tx *bolt.Tx, committed bool) {
+	db.mu.RLock()
+	pending := len(db.keyChanges) > 0
+	db.mu.RUnlock()
+
+	if !pending || tx == nil {
+		return
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	changes := db.keyChanges[tx]
+	delete(db.keyChanges, tx)
+
+	if !committed {
+		return
+	}
+
+	for _, change := range changes {
+		index := change.index
+
+		// Make a load racing with this transaction discard its keys
+		index.version++
+		if !index.loaded {
+			continue
+		}
+
+		idx := searchKeys(index.keys, change.key)
+		exists := idx < len(index.keys) && bytes.Equal(index.keys[idx], change.key)
+
+		switch {
+		case change.inserted && !exists:
+			index.keys = append(index.keys, nil)
+			copy(index.keys[idx+1:], index.keys[idx:])
+			index.keys[idx] = change.key
+		case !change.inserted && exists:
+			index.keys = append(index.keys[:idx], index.keys[idx+1:]...)
+		}
+	}
+}
+
+// searchKeys returns the index of the first of the sorted keys not smaller than the given key
+func searchKeys(keys [][]byte, key []byte) int {
+	return sort.Search(len(keys), func(i int) bool {
+		return bytes.Compare(keys[i], key) >= 0
+	})
+}
+
+// prefixKeys returns the sorted keys with the given prefix
+func prefixKeys(keys [][]byte, prefix []byte) [][]byte {
+	start := searchKeys(keys, prefix)
+
+	end := start
+	for end < len(keys) && bytes.HasPrefix(keys[end], prefix) {
+		end++
+	}
+
+	return keys[start:end]
+}
@@ -0,0 +1,231 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestKeyIndex(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{"key10": "value10", "key20": "value20", "key30": "value30"}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	check := func(indexed bool) {
+		exists, err := bucket.ExistsString("key20")
+		if err != nil || !exists {
+			t.Errorf("Existing key reported as missing with index %t. Error: %v", indexed, err)
+		}
+
+		count, err := bucket.CountPrefix([]byte("key"))
+		if err != nil || count != len(items) {
+			t.Errorf("Count %d does not match the keys inserted with index %t. Error: %v", count, indexed, err)
+		}
+
+		bounds := map[string][2]string{"key15": {"key10", "key20"}, "key20": {"key20", "key20"}, "key35": {"key30", ""}, "key05": {"", "key10"}}
+		for key, expected := range bounds {
+			floor, err := bucket.Floor([]byte(key))
+			if string(floor) != expected[0] || (expected[0] == "") != errors.Is(err, mbuckets.ErrKeyNotFound) {
+				t.Errorf("Floor %s of key %s does not match %s with index %t. Error: %v", floor, key, expected[0], indexed, err)
+			}
+
+			ceiling, err := bucket.Ceiling([]byte(key))
+			if string(ceiling) != expected[1] || (expected[1] == "") != errors.Is(err, mbuckets.ErrKeyNotFound) {
+				t.Errorf("Ceiling %s of key %s does not match %s with index %t. Error: %v", ceiling, key, expected[1], indexed, err)
+			}
+		}
+	}
+
+	t.Log("Checking key queries without index")
+	check(false)
+
+	t.Log("Enabling key index")
+	bucket.EnableKeyIndex()
+	check(true)
+
+	t.Log("Inserting a key with key index enabled")
+	err = bucket.InsertString("key40", "value40")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	keys, err := bucket.GetAllKeys()
+	if err != nil || fmt.Sprintf("%s", keys) != "[key10 key20 key30 key40]" {
+		t.Errorf("Indexed keys %s were not updated by insert. Error: %v", keys, err)
+	}
+
+	t.Log("Deleting the bucket with key index enabled")
+	err = bucket.DeleteBucket()
+	if err != nil {
+		t.Errorf("Unable to delete bucket. Error: %s", err.Error())
+	}
+
+	_, err = bucket.ExistsString("key20")
+	if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+		t.Errorf("Indexed key query on deleted bucket did not return ErrBucketNotFound. Error: %v", err)
+	}
+}
+
+func TestKeyIndexMaintained(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting key/value pairs and loading the key index")
+	err = bucket.InsertAllString(map[string]string{"key10": "value10", "key20": "value20"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	bucket.EnableKeyIndex()
+	_, err = bucket.Count()
+	if err != nil {
+		t.Errorf("Unable to count keys. Error: %s", err.Error())
+	}
+
+	t.Log("Failing every scan, so that reloading the key index fails")
+	errScan := errors.New("Scan failed")
+	db.SetFailpoints(mbuckets.Failpoints{Iterate: func(bucket, key []byte) error {
+		return errScan
+	}})
+
+	expectKeys := func(expected string) {
+		keys, err := bucket.GetAllKeys()
+		if err != nil || fmt.Sprintf("%s", keys) != expected {
+			t.Errorf("Indexed keys %s do not match %s. Error: %v", keys, expected, err)
+		}
+	}
+
+	t.Log("Inserting and deleting keys")
+	err = bucket.InsertString("key15", "value15")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	err = bucket.InsertString("key20", "value21")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	err = bucket.DeleteString("key10")
+	if err != nil {
+		t.Errorf("Unable to delete key. Error: %s", err.Error())
+	}
+
+	expectKeys("[key15 key20]")
+
+	t.Log("Inserting keys in a committed and a rolled back transaction")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		return tx.Bind(bucket).InsertString("key30", "value30")
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update in transaction. Error: %s", err.Error())
+	}
+
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		err := tx.Bind(bucket).InsertString("key40", "value40")
+		if err != nil {
+			return err
+		}
+		return errors.New("Abort")
+	})
+
+	if err == nil {
+		t.Errorf("Failing transaction did not return its error")
+	}
+
+	expectKeys("[key15 key20 key30]")
+
+	t.Log("Writing through a raw update")
+	err = db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("Bucket1")).Put([]byte("key50"), []byte("value50"))
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update. Error: %s", err.Error())
+	}
+
+	_, err = bucket.GetAllKeys()
+	if !errors.Is(err, errScan) {
+		t.Errorf("Key index was not reloaded after a raw update. Error: %v", err)
+	}
+
+	db.SetFailpoints(mbuckets.Failpoints{})
+	expectKeys("[key15 key20 key30 key50]")
+}
+
+func TestKeyIndexHookPanic(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Indexed")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	bucket.EnableKeyIndex()
+	count, err := bucket.Count()
+	if err != nil || count != 1 {
+		t.Errorf("Unable to load the key index: count %d. Error: %v", count, err)
+	}
+
+	t.Log("Inserting and deleting keys with an OnCommitInfo hook that panics")
+	db.RecoverPanics(true)
+	db.OnCommitInfo(func(mbuckets.CommitInfo) {
+		panic("simulated panic")
+	})
+
+	err = bucket.InsertString("key2", "value2")
+	if err == nil {
+		t.Errorf("Panic in OnCommitInfo hook was not returned")
+	}
+
+	err = bucket.Delete([]byte("key1"))
+	if err == nil {
+		t.Errorf("Panic in OnCommitInfo hook was not returned")
+	}
+
+	db.OnCommitInfo(nil)
+
+	exists, err := bucket.ExistsString("key2")
+	if err != nil || !exists {
+		t.Errorf("Key index does not include the committed key. Error: %v", err)
+	}
+
+	keys, err := bucket.GetAllKeys()
+	if err != nil || fmt.Sprintf("%s", keys) != "[key2]" {
+		t.Errorf("Key index %s does not match the committed keys. Error: %v", keys, err)
+	}
+}
@@ -0,0 +1,198 @@
+package mbuckets
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/boltdb/bolt"
+)
+
+// Limits on the shape of the bucket hierarchy, enforced when a Bucket creates its bolt.Buckets
+type Limits struct {
+	// Maximum number of nested levels in a bucket path, zero for no limit
+	Depth int
+
+	// Maximum number of bolt.Buckets directly under a bolt.Bucket, or at the top level of the DB, zero for no limit
+	FanOut int
+
+	// Called inside the write transaction when creating a bolt.Bucket exceeds a limit, and the bolt.Bucket is created anyway.
+	// If nil, the transaction fails with a *LimitError instead.
+	Warn func(*LimitError)
+}
+
+// LimitError describes a bolt.Bucket whose creation exceeds one of the Limits
+type LimitError struct {
+	// Complete hierarchial name of the Bucket being created
+	Bucket []byte
+
+	// Either "depth" or "fan-out"
+	Limit string
+
+	// The depth of the Bucket, or the number of bolt.Buckets under its parent including itself
+	Value int
+
+	// The exceeded limit
+	Max int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("Bucket %s exceeds %s limit: %d > %d", e.Bucket, e.Limit, e.Value, e.Max)
+}
+
+// SetLimits sets the limits on the depth and fan-out of the buckets created through this DB.
+// Buckets created directly through the embedded bolt.DB or by ImportItems are not checked.
+func (db *DB) SetLimits(limits Limits) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.limits = limits
+}
+
+// check verifies the creation of a bolt.Bucket at the given depth, with function `children` counting the existing siblings
+func (l Limits) check(name []byte, depth int, children func() int) error {
+	if l.Depth > 0 && depth > l.Depth {
+		if err := l.exceeded(&LimitError{name, "depth", depth, l.Depth}); err != nil {
+			return err
+		}
+	}
+
+	if l.FanOut > 0 {
+		if count := children() + 1; count > l.FanOut {
+			return l.exceeded(&LimitError{name, "fan-out", count, l.FanOut})
+		}
+	}
+
+	return nil
+}
+
+func (l Limits) exceeded(err *LimitError) error {
+	if l.Warn == nil {
+		return err
+	}
+
+	l.Warn(err)
+	return nil
+}
+
+// countRootBuckets counts the top level bolt.Buckets of the DB, stopping once the count reaches `max`
+func countRootBuckets(tx *bolt.Tx, max int) int {
+	count := 0
+	cursor := tx.Cursor()
+	for k, _ := cursor.First(); k != nil && count < max; k, _ = cursor.Next() {
+		if !isMetaBucket(k) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// countBuckets counts the bolt.Buckets nested directly under the given bolt.Bucket, stopping once the count reaches `max`
+func countBuckets(bucket *bolt.Bucket, max int) int {
+	count := 0
+	cursor := bucket.Cursor()
+	for k, v := cursor.First(); k != nil && count < max; k, v = cursor.Next() {
+		if v == nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+// depthPath is a bolt.Bucket found by DeepestPaths, `seq` being its position in the walk
+type depthPath struct {
+	segments [][]byte
+	depth    int
+	seq      int
+}
+
+// shallower reports whether path `p` ranks after path `other` in the result of DeepestPaths
+func (p depthPath) shallower(other depthPath) bool {
+	if p.depth != other.depth {
+		return p.depth < other.depth
+	}
+
+	return p.seq > other.seq
+}
+
+// depthHeap is a heap of the deepest paths found so far, with the shallowest one on top
+type depthHeap []depthPath
+
+func (h depthHeap) Len() int            { return len(h) }
+func (h depthHeap) Less(i, j int) bool  { return h[i].shallower(h[j]) }
+func (h depthHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *depthHeap) Push(x interface{}) { *h = append(*h, x.(depthPath)) }
+
+func (h *depthHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// DeepestPaths returns the names of the n most deeply nested bolt.Buckets in this DB, deepest first,
+// using "/" as the separator and escaping the names of the bolt.Buckets if enabled, see SetNameEscaping.
+// Only the n deepest paths are kept while walking the DB. An error is returned if n is negative.
+func (db *DB) DeepestPaths(n int) ([][]byte, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("Invalid number of paths: %d", n)
+	}
+
+	if n == 0 {
+		return [][]byte{}, nil
+	}
+
+	paths := make(depthHeap, 0, n)
+	seq := 0
+
+	add := func(segments [][]byte) {
+		path := depthPath{segments, len(segments), seq}
+		seq++
+
+		if len(paths) < n {
+			heap.Push(&paths, path)
+		} else if paths[0].shallower(path) {
+			paths[0] = path
+			heap.Fix(&paths, 0)
+		}
+	}
+
+	var walk func(segments [][]byte, bucket *bolt.Bucket)
+	walk = func(segments [][]byte, bucket *bolt.Bucket) {
+		bucket.ForEach(func(k, v []byte) error {
+			if v == nil {
+				child := make([][]byte, len(segments), len(segments)+1)
+				copy(child, segments)
+				child = append(child, copyKey(k))
+				add(child)
+				walk(child, bucket.Bucket(k))
+			}
+			return nil
+		})
+	}
+
+	err := db.Map(func(k []byte, bucket *bolt.Bucket) error {
+		segments := [][]byte{copyKey(k)}
+		add(segments)
+		walk(segments, bucket)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[j].shallower(paths[i])
+	})
+
+	separator := []byte("/")
+	names := make([][]byte, len(paths))
+	for idx, path := range paths {
+		names[idx] = db.joinNames(path.segments, separator)
+	}
+
+	return names, nil
+}
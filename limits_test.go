@@ -0,0 +1,146 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestLimits(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Setting limits: depth 2, fan-out 2")
+	db.SetLimits(mbuckets.Limits{Depth: 2, FanOut: 2})
+
+	for _, name := range []string{"Bucket1", "Bucket2", "Bucket1/Bucket3", "Bucket1/Bucket4"} {
+		t.Logf("Creating bucket within limits: %s", name)
+		err = db.BucketString(name).CreateBucket()
+		if err != nil {
+			t.Errorf("Unable to create bucket within limits. Error: %s", err.Error())
+		}
+	}
+
+	for name, limit := range map[string]string{"Bucket5": "fan-out", "Bucket1/Bucket5": "fan-out", "Bucket2/Bucket3/Bucket4": "depth"} {
+		t.Logf("Creating bucket exceeding %s limit: %s", limit, name)
+		err = db.BucketString(name).CreateBucket()
+
+		var limitErr *mbuckets.LimitError
+		if !errors.As(err, &limitErr) || limitErr.Limit != limit {
+			t.Errorf("Creation of bucket exceeding %s limit did not fail with a LimitError. Error: %v", limit, err)
+		}
+	}
+
+	exists, err := db.BucketExists([]byte("Bucket2/Bucket3"))
+	if err != nil || exists {
+		t.Error("Parent bucket of a bucket exceeding limits was created")
+	}
+
+	t.Log("Setting limits with a warning function")
+	var warnings []string
+	db.SetLimits(mbuckets.Limits{Depth: 2, FanOut: 2, Warn: func(err *mbuckets.LimitError) {
+		warnings = append(warnings, err.Limit)
+	}})
+
+	err = db.BucketString("Bucket1/Bucket4/Bucket5").CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create bucket exceeding soft limits. Error: %s", err.Error())
+	}
+
+	if fmt.Sprint(warnings) != "[depth]" {
+		t.Errorf("Warnings %v do not match the exceeded limits", warnings)
+	}
+
+	t.Log("Retrieving the 2 deepest paths")
+	paths, err := db.DeepestPaths(2)
+	if err != nil {
+		t.Errorf("Unable to retrieve the deepest paths. Error: %s", err.Error())
+	}
+
+	if fmt.Sprintf("%s", paths) != "[Bucket1/Bucket4/Bucket5 Bucket1/Bucket3]" {
+		t.Errorf("Deepest paths %s do not match the buckets created", paths)
+	}
+}
+
+func TestDeepestPaths(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	db.SetNameEscaping(true)
+
+	for _, name := range []string{`a\/b/c/d`, "e/f", "g"} {
+		t.Logf("Creating bucket: %s", name)
+		err = db.BucketString(name).CreateBucket()
+		if err != nil {
+			t.Errorf("Unable to create bucket. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Retrieving the deepest paths with a negative count")
+	_, err = db.DeepestPaths(-1)
+	if err == nil {
+		t.Errorf("DeepestPaths did not fail with a negative count")
+	}
+
+	paths, err := db.DeepestPaths(0)
+	if err != nil || len(paths) != 0 {
+		t.Errorf("DeepestPaths(0) returned %s. Error: %v", paths, err)
+	}
+
+	t.Log("Retrieving the 3 deepest paths")
+	paths, err = db.DeepestPaths(3)
+	if err != nil {
+		t.Errorf("Unable to retrieve the deepest paths. Error: %s", err.Error())
+	}
+
+	if fmt.Sprintf("%s", paths) != `[a\/b/c/d a\/b/c e/f]` {
+		t.Errorf("Deepest paths %s do not match the escaped bucket names", paths)
+	}
+}
+
+func TestFanOutCountsBuckets(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Inserting keys into a bucket")
+	for idx := 0; idx < 5; idx++ {
+		err = db.BucketString("Parent").InsertString(fmt.Sprintf("key%d", idx), "value")
+		if err != nil {
+			t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+		}
+	}
+
+	db.SetLimits(mbuckets.Limits{FanOut: 2})
+
+	t.Log("Creating nested buckets next to the keys")
+	for _, name := range []string{"Parent/Child1", "Parent/Child2"} {
+		err = db.BucketString(name).CreateBucket()
+		if err != nil {
+			t.Errorf("Keys were counted towards the fan-out limit. Error: %s", err.Error())
+		}
+	}
+
+	err = db.BucketString("Parent/Child3").CreateBucket()
+
+	var limitErr *mbuckets.LimitError
+	if !errors.As(err, &limitErr) || limitErr.Value != 3 {
+		t.Errorf("Creation of bucket exceeding fan-out limit did not fail with a LimitError. Error: %v", err)
+	}
+}
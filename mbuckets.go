@@ -8,8 +8,11 @@ package mbuckets
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -18,46 +21,171 @@ import (
 // DB embeds a bolt.DB
 type DB struct {
 	*bolt.DB
+
+	mu sync.RWMutex
+
+	// Cached bucket names keyed by separator, nil when caching is disabled
+	bucketNameCache map[string][][]byte
+
+	// Generation counters of bucket paths, bumped on structural changes made through this DB
+	generations map[string]uint64
+
+	// Called with the details of every committed write transaction
+	commitInfoHook func(CommitInfo)
+
+	closed bool
+
+	// Number of references taken with Acquire and not yet released
+	refs int
+
+	// Whether panics in functions called by mbuckets are converted into errors
+	recoverPanics bool
+
+	// Receives an Event for every mutating call
+	eventSink EventSink
+
+	// Limits on the shape of the bucket hierarchy
+	limits Limits
+
+	// Source of the current time, nil for the system clock
+	clock Clock
+
+	// Errors injected into operations by tests
+	failpoints Failpoints
+
+	// Size above which values put through a Bucket are reported to valueSizeWarning, zero for no limit
+	valueSizeLimit   int
+	valueSizeWarning func(bucket, key []byte, size int)
+
+	// Traversal statistics keyed by bucket name, nil when tracking is disabled
+	traversal map[string]*TraversalStat
+
+	// In-memory key indexes keyed by the pathKey of their bucket path
+	keyIndexes map[string]*keyIndex
+
+	// Keys inserted into or removed from indexed bolt.Buckets by open transactions
+	keyChanges map[*bolt.Tx][]keyChange
+
+	// Whether separators in bucket names may be escaped
+	nameEscaping bool
+
+	// Whether writes through all Buckets fail with ErrBucketNotFound instead of creating missing bolt.Buckets
+	strict bool
+
+	// Whether Map callbacks get copies of the key/value slices that are poisoned after the transaction
+	slicePoisoning bool
+
+	// ScanErrorPolicy of the Buckets that do not set their own, zero for ScanStop
+	scanPolicy ScanErrorPolicy
+
+	// Number of keys deleted through Buckets
+	deletedKeys atomic.Uint64
+
+	// Hooks called with the Mutations of committed and rolled back write transactions
+	commitHook   func([]Mutation)
+	rollbackHook func([]Mutation, error)
+
+	// Registration of this DB for CheckSingleWriter, nil if not registered
+	handle *handle
+
+	// TxStats of the open transactions, nil when tracking is disabled, and of the last finished one
+	txStats     map[*bolt.Tx]*TxStat
+	lastTxStat  *TxStat
+	txStatsHook func(TxStat)
+
+	// Maximum duration of write transactions, zero for no limit
+	writeTimeout time.Duration
+
+	// State removed by Recover when this DB was opened
+	recovery RecoveryReport
 }
 
 // Open creates/opens a bolt.DB at specified path, and returns a DB enclosing the same
 func Open(path string) (*DB, error) {
-	database, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
-	if err != nil {
-		return nil, err
-	}
-
-	return &DB{database}, nil
+	return OpenWith(path, 0600, nil)
 }
 
-// OpenWith creates/opens a bolt.DB at specified path with given permissions and options, and returns a DB enclosing the same
+// OpenWith creates/opens a bolt.DB at specified path with given permissions and options, and returns a DB enclosing the same.
+// Unless the bolt.DB is opened read-only, state left over by crashed processes is removed first, see Recover.
 func OpenWith(path string, mode os.FileMode, options *bolt.Options) (*DB, error) {
 	if options == nil {
 		options = &bolt.Options{Timeout: 1 * time.Second}
 	}
 
+	handle, err := openHandle(path, options.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
 	database, err := bolt.Open(path, mode, options)
 	if err != nil {
+		closeHandle(handle)
 		return nil, err
 	}
 
-	return &DB{database}, nil
+	db := &DB{DB: database, handle: handle}
+	if options.ReadOnly {
+		return db, nil
+	}
+
+	recovery, err := db.Recover()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	db.recovery = recovery
+	return db, nil
 }
 
-// Close closes the embedded bolt.DB
+// Close closes the embedded bolt.DB.
+// All operations on this DB return ErrDBClosed afterwards.
 func (db *DB) Close() error {
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return nil
+	}
+	db.closed = true
+	db.mu.Unlock()
+
+	defer closeHandle(db.handle)
 	return db.DB.Close()
 }
 
+// IsClosed reports whether Close has been called on this DB
+func (db *DB) IsClosed() bool {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.closed
+}
+
 // Map applies read only function `fn` on all the top level buckets in this DB
 func (db *DB) Map(fn func([]byte, *bolt.Bucket) error) error {
 	return db.View(func(tx *bolt.Tx) error {
-		return tx.ForEach(fn)
+		return stopped(tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+			if isMetaBucket(name) {
+				return nil
+			}
+
+			return fn(name, bucket)
+		}))
 	})
 }
 
 // GetRootBucketNames returns all the top level bolt.Bucket names in this DB
 func (db *DB) GetRootBucketNames() ([][]byte, error) {
+	bucketNames, err := db.rootNames()
+
+	for idx, name := range bucketNames {
+		bucketNames[idx] = db.listedName(name, []byte("/"))
+	}
+
+	return bucketNames, err
+}
+
+func (db *DB) rootNames() ([][]byte, error) {
 	var bucketNames [][]byte
 
 	err := db.Map(func(name []byte, _ *bolt.Bucket) error {
@@ -72,39 +200,37 @@ func (db *DB) GetRootBucketNames() ([][]byte, error) {
 
 // GetAllBucketNames recursively finds and returns all the bolt.Bucket names in this DB
 func (db *DB) GetAllBucketNames() ([][]byte, error) {
-	bucketNames, err := db.GetRootBucketNames()
-	if err != nil {
-		return nil, err
-	}
+	return db.GetAllBucketNamesWithSeparator([]byte("/"))
+}
 
-	var allBucketNames [][]byte
+// GetAllBucketNamesWithSeparator recursively finds and returns all the bolt.Bucket names in this DB using specified separator
+func (db *DB) GetAllBucketNamesWithSeparator(separator []byte) ([][]byte, error) {
+	if names, ok := db.cachedBucketNames(separator); ok {
+		return names, nil
+	}
 
-	for _, bucketName := range bucketNames {
-		bucket := db.Bucket(bucketName)
-		allBucketNames = append(allBucketNames, bucketName)
+	generation := db.Generation()
 
-		subBucketNames, err := bucket.GetAllBucketNames()
-		if err != nil {
-			return allBucketNames, err
-		}
-		allBucketNames = append(allBucketNames, subBucketNames...)
+	names, err := db.scanAllBucketNames(separator)
+	if err != nil {
+		return names, err
 	}
 
-	return allBucketNames, nil
+	db.cacheBucketNames(separator, names, generation)
+	return names, nil
 }
 
-// GetAllBucketNamesWithSeparator recursively finds and returns all the bolt.Bucket names in this DB using specified separator
-func (db *DB) GetAllBucketNamesWithSeparator(separator []byte) ([][]byte, error) {
-	bucketNames, err := db.GetRootBucketNames()
+func (db *DB) scanAllBucketNames(separator []byte) ([][]byte, error) {
+	rootNames, err := db.rootNames()
 	if err != nil {
 		return nil, err
 	}
 
 	var allBucketNames [][]byte
 
-	for _, bucketName := range bucketNames {
-		bucket := db.Bucket(bucketName).WithSeparator(separator)
-		allBucketNames = append(allBucketNames, bucketName)
+	for _, rootName := range rootNames {
+		bucket := &Bucket{DB: db, Name: db.listedName(rootName, separator), Separator: separator, path: [][]byte{rootName}}
+		allBucketNames = append(allBucketNames, bucket.Name)
 
 		subBucketNames, err := bucket.GetAllBucketNames()
 		if err != nil {
@@ -120,16 +246,44 @@ func (db *DB) GetAllBucketNamesWithSeparator(separator []byte) ([][]byte, error)
 type Bucket struct {
 	DB *DB
 
-	// Complete hierarchial name of the Bucket
+	// Complete hierarchial name of the Bucket.
+	// The names of the nested bolt.Buckets are split from it when the Bucket is created, so it must not be changed afterwards.
 	Name []byte
 
 	// The Bucket Name separator
 	Separator []byte
+
+	// The order of Items returned by GetAll, GetPrefix and GetRange, nil for key order
+	Sort ResultSort
+
+	// Names of the nested bolt.Buckets, split from Name when nil
+	path [][]byte
+
+	// Whether path was given as explicit segments by BucketAt instead of split from Name
+	explicit bool
+
+	// Whether writes fail with ErrBucketNotFound instead of creating missing bolt.Buckets
+	strict bool
+
+	// Fencing token carried by writes, checked only if fenced is set
+	fence  uint64
+	fenced bool
+
+	// FillPercent set on the bolt.Bucket in write transactions, zero for bolt's default
+	fillPercent float64
+
+	// The Tx all operations run in, nil to run each operation in its own transaction
+	tx *Tx
+
+	// ScanErrorPolicy of Map, MapPrefix and MapRange, zero for the default of the DB
+	scanPolicy ScanErrorPolicy
 }
 
-// Bucket returns a pointer to a Bucket in this DB
+// Bucket returns a pointer to a Bucket in this DB.
+// The name "__mbuckets__" is reserved, all operations on a Bucket under it fail with an error wrapping ErrReservedBucket.
 func (db *DB) Bucket(name []byte) *Bucket {
-	return &Bucket{db, name, []byte("/")}
+	separator := []byte("/")
+	return &Bucket{DB: db, Name: name, Separator: separator, path: db.splitName(name, separator)}
 }
 
 // BucketString is a convenience wrapper over Bucket for string name
@@ -137,52 +291,228 @@ func (db *DB) BucketString(name string) *Bucket {
 	return db.Bucket([]byte(name))
 }
 
+// BucketAt returns a pointer to a Bucket in this DB for the nested bolt.Buckets with the given names.
+//
+// The names are used as is, so they may contain the separator.
+// The Name of the returned Bucket joins them with the separator, and is only used to identify the Bucket in errors and Events.
+// As with Bucket, the first name must not be "__mbuckets__".
+func (db *DB) BucketAt(segments ...[]byte) *Bucket {
+	if len(segments) == 0 {
+		segments = [][]byte{nil}
+	}
+
+	path := make([][]byte, len(segments))
+	copy(path, segments)
+
+	separator := []byte("/")
+	return &Bucket{DB: db, Name: db.joinNames(path, separator), Separator: separator, path: path, explicit: true}
+}
+
+// BucketAtString is a convenience wrapper over BucketAt for string names
+func (db *DB) BucketAtString(segments ...string) *Bucket {
+	path := make([][]byte, len(segments))
+	for idx, segment := range segments {
+		path[idx] = []byte(segment)
+	}
+
+	return db.BucketAt(path...)
+}
+
+// SetStrict sets whether writes through the Buckets of this DB fail with ErrBucketNotFound
+// when the bolt.Bucket they are made on does not exist, instead of creating it and all bolt.Buckets above it.
+// Use CreateBucket or EnsurePath to create bolt.Buckets in strict mode.
+func (db *DB) SetStrict(strict bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.strict = strict
+}
+
+// BucketExists reports whether the bolt.Bucket with the given "/" separated name exists, without creating it
+func (db *DB) BucketExists(name []byte) (bool, error) {
+	return db.Bucket(name).BucketExists()
+}
+
 // WithSeparator overrides the separator for this Bucket with the given separator and returns a pointer to this Bucket.
 //
 // The name of this Bucket and all Buckets under it must be separated by the given custom separator.
 // Note that it is an error to mix different separators and can lead to unexpected behavior.
 func (b *Bucket) WithSeparator(separator []byte) *Bucket {
 	b.Separator = separator
+
+	if b.explicit {
+		b.Name = b.DB.joinNames(b.path, separator)
+	} else {
+		b.path = b.DB.splitName(b.Name, separator)
+	}
+
 	return b
 }
 
-// Update performs an update operation specified by function `fn` on this Bucket
+// segments returns the names of the nested bolt.Buckets specified by this Bucket.
+// The returned slice is shared, appending to it always copies.
+func (b *Bucket) segments() [][]byte {
+	if b.path == nil {
+		return b.DB.splitName(b.Name, b.Separator)
+	}
+
+	return b.path[:len(b.path):len(b.path)]
+}
+
+// Update performs an update operation specified by function `fn` on this Bucket.
+// Function `fn` may be retried once, see DB.Update.
 func (b *Bucket) Update(fn func(*bolt.Bucket, *bolt.Tx) error) error {
-	buckets := bytes.Split(b.Name, b.Separator)
+	buckets := b.segments()
+	defer b.afterCommit(func() {
+		b.DB.invalidateKeyIndexes(buckets)
+		b.DB.structureChanged(buckets, true)
+	})
 
-	return b.DB.Update(func(tx *bolt.Tx) error {
-		bucket, err := tx.CreateBucketIfNotExists(buckets[0])
+	return b.mutate(OpUpdate, nil, fn)
+}
+
+func (b *Bucket) update(fn func(*bolt.Bucket, *bolt.Tx) error) error {
+	return b.updatePath(!b.isStrict(), fn)
+}
+
+// updatePath performs an update operation specified by function `fn` on this Bucket,
+// creating the nested bolt.Buckets specified by this Bucket if `create` is set
+func (b *Bucket) updatePath(create bool, fn func(*bolt.Bucket, *bolt.Tx) error) error {
+	return b.updatePathWith(b.write, create, fn)
+}
+
+// updatePathWith is updatePath running the write transaction with function `run`
+func (b *Bucket) updatePathWith(run func(func(*bolt.Tx) error) error, create bool, fn func(*bolt.Bucket, *bolt.Tx) error) error {
+	buckets := b.segments()
+
+	b.DB.mu.RLock()
+	limits := b.DB.limits
+	b.DB.mu.RUnlock()
+
+	created := false
+	var current *bolt.Tx
+
+	err := run(func(tx *bolt.Tx) error {
+		if current != nil && current != tx {
+			b.DB.finishKeyChanges(current, false)
+		}
+		current = tx
+
+		bucket, isNew, err := b.createPath(tx, buckets, limits, create)
 		if err != nil {
 			return err
 		}
 
-		if len(buckets) > 1 {
-			for idx, bucketName := range buckets {
-				if idx == 0 {
-					continue
-				}
+		created = isNew
+
+		err = b.checkFence(tx, buckets)
+		if err != nil {
+			return err
+		}
+
+		if b.fillPercent > 0 {
+			bucket.FillPercent = b.fillPercent
+		}
+
+		return fn(bucket, tx)
+	})
+
+	if b.tx == nil {
+		b.DB.finishKeyChanges(current, false)
+	}
+
+	if err == nil && created {
+		b.afterCommit(func() { b.DB.structureChanged(buckets, false) })
+	}
 
-				subBucket, err := bucket.CreateBucketIfNotExists(bucketName)
+	b.DB.traversed(b.Name, len(buckets))
+	return err
+}
+
+// createPath creates the nested bolt.Buckets with the given names specified by this Bucket if they do not exist,
+// and reports whether any of them was created. If `create` is not set, missing bolt.Buckets are an error instead.
+func (b *Bucket) createPath(tx *bolt.Tx, buckets [][]byte, limits Limits, create bool) (*bolt.Bucket, bool, error) {
+	if !create {
+		bucket, err := b.findPath(tx, buckets)
+		return bucket, false, err
+	}
+
+	created := tx.Bucket(buckets[0]) == nil
+	if created {
+		err := limits.check(b.Name, 1, func() int { return countRootBuckets(tx, limits.FanOut) })
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	bucket, err := tx.CreateBucketIfNotExists(buckets[0])
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(buckets) > 1 {
+		for idx, bucketName := range buckets {
+			if idx == 0 {
+				continue
+			}
+
+			if bucket.Bucket(bucketName) == nil {
+				created = true
+
+				parent := bucket
+				err := limits.check(b.Name, idx+1, func() int { return countBuckets(parent, limits.FanOut) })
 				if err != nil {
-					return err
+					return nil, false, err
 				}
+			}
 
-				bucket = subBucket
+			subBucket, err := bucket.CreateBucketIfNotExists(bucketName)
+			if err != nil {
+				return nil, false, err
 			}
+
+			bucket = subBucket
 		}
+	}
 
-		return fn(bucket, tx)
-	})
+	return bucket, created, nil
+}
+
+// findPath returns the nested bolt.Bucket with the given names specified by this Bucket
+func (b *Bucket) findPath(tx *bolt.Tx, buckets [][]byte) (*bolt.Bucket, error) {
+	if err := b.checkReserved(); err != nil {
+		return nil, err
+	}
+
+	bucket := tx.Bucket(buckets[0])
+	if bucket == nil {
+		return nil, bucketNotFound(b.Name)
+	}
+
+	for _, bucketName := range buckets[1:] {
+		bucket = bucket.Bucket(bucketName)
+		if bucket == nil {
+			return nil, bucketNotFound(b.Name)
+		}
+	}
+
+	return bucket, nil
 }
 
 // View performs a view operation specified by function `fn` on this Bucket
 func (b *Bucket) View(fn func(*bolt.Bucket, *bolt.Tx) error) error {
-	buckets := bytes.Split(b.Name, b.Separator)
+	buckets := b.segments()
+	hops := 1
+
+	defer func() {
+		b.DB.traversed(b.Name, hops)
+	}()
 
-	return b.DB.View(func(tx *bolt.Tx) error {
+	return b.read(func(tx *bolt.Tx) error {
+		hops = 1
 		bucket := tx.Bucket(buckets[0])
 		if bucket == nil {
-			return fmt.Errorf("Bucket not found: %s", b.Name)
+			return bucketNotFound(b.Name)
 		}
 
 		if len(buckets) > 1 {
@@ -191,9 +521,10 @@ func (b *Bucket) View(fn func(*bolt.Bucket, *bolt.Tx) error) error {
 					continue
 				}
 
+				hops++
 				subBucket := bucket.Bucket(bucketName)
 				if subBucket == nil {
-					return fmt.Errorf("Bucket not found: %s", b.Name)
+					return bucketNotFound(b.Name)
 				}
 
 				bucket = subBucket
@@ -204,25 +535,83 @@ func (b *Bucket) View(fn func(*bolt.Bucket, *bolt.Tx) error) error {
 	})
 }
 
-// CreateBucket cretes the bolt.Bucket specified by this Bucket
-func (b *Bucket) CreateBucket() error {
-	return b.Update(func(*bolt.Bucket, *bolt.Tx) error {
+// BucketExists reports whether the bolt.Bucket specified by this Bucket exists, without creating it
+func (b *Bucket) BucketExists() (bool, error) {
+	err := b.View(func(*bolt.Bucket, *bolt.Tx) error {
 		return nil
 	})
+
+	if errors.Is(err, ErrBucketNotFound) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// CreateBucket cretes the bolt.Bucket specified by this Bucket, also in strict mode
+func (b *Bucket) CreateBucket() error {
+	return b.track(OpCreateBucket, nil, func() error {
+		return b.updatePath(true, func(*bolt.Bucket, *bolt.Tx) error {
+			return nil
+		})
+	})
+}
+
+// EnsurePath creates the bolt.Bucket specified by this Bucket and all bolt.Buckets above it if they do not exist,
+// also in strict mode. It is the intentional counterpart of the implicit creation done by writes outside strict mode.
+func (b *Bucket) EnsurePath() error {
+	return b.CreateBucket()
+}
+
+// WithFillPercent sets the bolt.Bucket FillPercent used by all writes through this Bucket and returns a pointer to this Bucket.
+//
+// A FillPercent close to 1.0 packs the pages of bulk loads of increasing keys, such as InsertAll or InsertNext,
+// into fewer pages. It applies to the write transactions of this Bucket only and is not stored in the database,
+// so other Buckets and transactions keep using bolt.DefaultFillPercent. Passing zero reverts to the default.
+func (b *Bucket) WithFillPercent(fillPercent float64) *Bucket {
+	b.fillPercent = fillPercent
+	return b
+}
+
+// Strict turns on strict mode for this Bucket and returns a pointer to this Bucket, see DB.SetStrict
+func (b *Bucket) Strict() *Bucket {
+	b.strict = true
+	return b
+}
+
+func (b *Bucket) isStrict() bool {
+	b.DB.mu.RLock()
+	defer b.DB.mu.RUnlock()
+
+	return b.strict || b.DB.strict
 }
 
 // DeleteBucket deletes the bolt.Bucket specified by this Bucket
 func (b *Bucket) DeleteBucket() error {
-	buckets := bytes.Split(b.Name, b.Separator)
+	return b.track(OpDeleteBucket, nil, b.deleteBucket)
+}
+
+func (b *Bucket) deleteBucket() error {
+	buckets := b.segments()
+	defer b.afterCommit(func() { b.DB.invalidateKeyIndexes(buckets) })
+
+	err := b.write(func(tx *bolt.Tx) error {
+		if containsWriteOnce(tx, buckets) {
+			return writeOnceError(b.Name, nil)
+		}
+
+		err := clearFences(tx, buckets)
+		if err != nil {
+			return err
+		}
 
-	return b.DB.Update(func(tx *bolt.Tx) error {
 		if len(buckets) == 1 {
-			return tx.DeleteBucket(buckets[0])
+			return bucketError(tx.DeleteBucket(buckets[0]), b.Name)
 		}
 
 		bucket := tx.Bucket(buckets[0])
 		if bucket == nil {
-			return fmt.Errorf("Bucket not found: %s", b.Name)
+			return bucketNotFound(b.Name)
 		}
 
 		for idx, bucketName := range buckets {
@@ -231,12 +620,12 @@ func (b *Bucket) DeleteBucket() error {
 			}
 
 			if idx == len(buckets)-1 {
-				return bucket.DeleteBucket(bucketName)
+				return bucketError(bucket.DeleteBucket(bucketName), b.Name)
 			}
 
 			subBucket := bucket.Bucket(bucketName)
 			if subBucket == nil {
-				return fmt.Errorf("Bucket not found: %s", b.Name)
+				return bucketNotFound(b.Name)
 			}
 
 			bucket = subBucket
@@ -244,45 +633,64 @@ func (b *Bucket) DeleteBucket() error {
 
 		return nil
 	})
+
+	if err == nil {
+		b.afterCommit(func() { b.DB.structureChanged(buckets, true) })
+	}
+
+	return err
 }
 
 // Map performs a view operation specified by function `fn` on all key value pairs in this Bucket
 func (b *Bucket) Map(fn func([]byte, []byte) error) error {
-	return b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
-		return bucket.ForEach(fn)
-	})
+	fn, finish := b.scanErrors(fn)
+	fn, release := b.DB.poisonSlices(b.Name, b.DB.iterateFailpoint(b.Name, fn))
+	defer release()
+
+	return finish(b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return stopped(bucket.ForEach(fn))
+	}))
 }
 
 // MapPrefix performs a view operation specified by function `fn` on all key value pairs in this Bucket with the given prefix
 func (b *Bucket) MapPrefix(prefix []byte, fn func([]byte, []byte) error) error {
-	return b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+	fn, finish := b.scanErrors(fn)
+	fn, release := b.DB.poisonSlices(b.Name, b.DB.iterateFailpoint(b.Name, fn))
+	defer release()
+
+	return finish(b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
 		cursor := bucket.Cursor()
 
-		for k, v := cursor.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
 			err := fn(k, v)
 			if err != nil {
-				return err
+				return stopped(err)
 			}
 		}
 
 		return nil
-	})
+	}))
 }
 
-// MapRange performs a view operation specified by function `fn` on all key value pairs in this Bucket within the given range
+// MapRange performs a view operation specified by function `fn` on all key value pairs in this Bucket within the given range.
+// Both bounds are inclusive, see MapRangeWith for other bounds.
 func (b *Bucket) MapRange(min, max []byte, fn func([]byte, []byte) error) error {
-	return b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+	fn, finish := b.scanErrors(fn)
+	fn, release := b.DB.poisonSlices(b.Name, b.DB.iterateFailpoint(b.Name, fn))
+	defer release()
+
+	return finish(b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
 		cursor := bucket.Cursor()
 
 		for k, v := cursor.Seek(min); k != nil && bytes.Compare(k, max) <= 0; k, v = cursor.Next() {
 			err := fn(k, v)
 			if err != nil {
-				return err
+				return stopped(err)
 			}
 		}
 
 		return nil
-	})
+	}))
 }
 
 // Item represents a holder for a key value pair
@@ -293,21 +701,58 @@ type Item struct {
 
 // Insert puts a single key/value pair in the bolt.Bucket specified by this Bucket
 func (b *Bucket) Insert(key, value []byte) error {
-	return b.Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
-		return bucket.Put(key, value)
+	return b.mutate(OpInsert, key, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return b.put(bucket, key, value)
+	})
+}
+
+// InsertBatched puts a single key/value pair in the bolt.Bucket specified by this Bucket like Insert,
+// but shares the write transaction with concurrent calls to InsertBatched and DB.Batch, see bolt.DB.Batch.
+// This trades a delay of up to bolt.DB.MaxBatchDelay for far fewer commits when many goroutines insert at once.
+func (b *Bucket) InsertBatched(key, value []byte) error {
+	return b.track(OpInsert, key, func() error {
+		return b.updatePathWith(b.writeBatched, !b.isStrict(), func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+			return b.put(bucket, key, value)
+		})
 	})
 }
 
+// InsertBatchedString is a convenience wrapper over InsertBatched for string key value pair
+func (b *Bucket) InsertBatchedString(key, value string) error {
+	return b.InsertBatched([]byte(key), []byte(value))
+}
+
 // InsertString is a convenience wrapper over Insert for string key value pair
 func (b *Bucket) InsertString(key, value string) error {
 	return b.Insert([]byte(key), []byte(value))
 }
 
+// InsertIfAbsent puts a single key/value pair in the bolt.Bucket specified by this Bucket only if the key does not exist,
+// and reports whether the key/value pair was put
+func (b *Bucket) InsertIfAbsent(key, value []byte) (inserted bool, err error) {
+	err = b.mutate(OpInsertIfAbsent, key, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		inserted = false
+		if bucket.Get(key) != nil {
+			return nil
+		}
+
+		inserted = true
+		return b.put(bucket, key, value)
+	})
+
+	return inserted && err == nil, err
+}
+
+// InsertIfAbsentString is a convenience wrapper over InsertIfAbsent for string key value pair
+func (b *Bucket) InsertIfAbsentString(key, value string) (bool, error) {
+	return b.InsertIfAbsent([]byte(key), []byte(value))
+}
+
 // InsertAll puts multiple key/value pairs in the bolt.Bucket specified by this Bucket
 func (b *Bucket) InsertAll(items []Item) error {
-	return b.Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+	return b.mutate(OpInsertAll, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
 		for _, item := range items {
-			err := bucket.Put(item.Key, item.Value)
+			err := b.put(bucket, item.Key, item.Value)
 			if err != nil {
 				return err
 			}
@@ -318,9 +763,9 @@ func (b *Bucket) InsertAll(items []Item) error {
 
 // InsertAllString is a convenience method to Insert string key value pairs
 func (b *Bucket) InsertAllString(items map[string]string) error {
-	return b.Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+	return b.mutate(OpInsertAll, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
 		for key, value := range items {
-			err := bucket.Put([]byte(key), []byte(value))
+			err := b.put(bucket, []byte(key), []byte(value))
 			if err != nil {
 				return err
 			}
@@ -329,12 +774,64 @@ func (b *Bucket) InsertAllString(items map[string]string) error {
 	})
 }
 
+// UpdateKeys replaces the values of the given keys in the bolt.Bucket specified by this Bucket in a single transaction.
+//
+// Function `fn` is called for every key with a copy of its current value, or nil if the key does not exist,
+// and returns the new value for the key, or nil to delete it.
+// If `fn` returns an error, none of the keys are updated.
+func (b *Bucket) UpdateKeys(keys [][]byte, fn func(key, old []byte) ([]byte, error)) error {
+	return b.mutate(OpUpdateKeys, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		for _, key := range keys {
+			var old []byte
+			if v := bucket.Get(key); v != nil {
+				old = make([]byte, len(v))
+				copy(old, v)
+			}
+
+			value, err := fn(key, old)
+			if err != nil {
+				return err
+			}
+
+			if value == nil {
+				err = b.delete(bucket, key)
+			} else {
+				err = b.put(bucket, key, value)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CompareAndSwap puts the given value for the given key in the bolt.Bucket specified by this Bucket
+// only if the current value of the key equals `old`, or if the key does not exist when `old` is nil,
+// and reports whether the value was put
+func (b *Bucket) CompareAndSwap(key, old, value []byte) (swapped bool, err error) {
+	err = b.mutate(OpCompareAndSwap, key, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		swapped = false
+
+		current := bucket.Get(key)
+		if (old == nil) != (current == nil) || !bytes.Equal(current, old) {
+			return nil
+		}
+
+		swapped = true
+		return b.put(bucket, key, value)
+	})
+
+	return swapped && err == nil, err
+}
+
 // Get retrieves the value for given a key from the bolt.Bucket specified by this Bucket
 func (b *Bucket) Get(key []byte) (value []byte, err error) {
 	err = b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
 		v := bucket.Get(key)
 		if v == nil {
-			return fmt.Errorf("Key not found: %s", key)
+			return keyNotFound(key)
 		}
 
 		value = make([]byte, len(v))
@@ -350,7 +847,7 @@ func (b *Bucket) GetString(key string) (value string, err error) {
 	err = b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
 		v := bucket.Get([]byte(key))
 		if v == nil {
-			return fmt.Errorf("Key not found: %s", key)
+			return keyNotFound([]byte(key))
 		}
 
 		value = string(v)
@@ -360,6 +857,139 @@ func (b *Bucket) GetString(key string) (value string, err error) {
 	return value, err
 }
 
+// GetMany retrieves the values for the given keys from the bolt.Bucket specified by this Bucket in a single transaction.
+//
+// Items are returned in the order of the given keys. If some of the keys do not exist,
+// the Items found are returned along with a *MissingKeysError listing the missing keys.
+func (b *Bucket) GetMany(keys [][]byte) (items []Item, err error) {
+	var getErr error
+
+	err = b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		items, getErr = getMany(bucket, keys)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return items, getErr
+}
+
+// GetManyString is a convenience method to GetMany string key value pairs
+func (b *Bucket) GetManyString(keys []string) (map[string]string, error) {
+	byteKeys := make([][]byte, len(keys))
+	for idx, key := range keys {
+		byteKeys[idx] = []byte(key)
+	}
+
+	items, err := b.GetMany(byteKeys)
+
+	result := make(map[string]string, len(items))
+	for _, item := range items {
+		result[string(item.Key)] = string(item.Value)
+	}
+
+	return result, err
+}
+
+// KeyRequest names keys to retrieve from the bolt.Bucket specified by a Bucket, see DB.GetManyAcross
+type KeyRequest struct {
+	Bucket *Bucket
+	Keys   [][]byte
+}
+
+// Result holds the Items retrieved for a KeyRequest
+type Result struct {
+	// Items found, in the order of the requested keys
+	Items []Item
+
+	// An error wrapping ErrBucketNotFound if the bolt.Bucket does not exist,
+	// or a *MissingKeysError if some of the keys do not exist
+	Err error
+}
+
+// GetManyAcross retrieves the keys of every KeyRequest from the bolt.Buckets specified by their Buckets in a single transaction,
+// and returns one Result per KeyRequest in the same order.
+// The returned error is only set if the transaction itself fails, errors of a single KeyRequest are reported in its Result.
+func (db *DB) GetManyAcross(requests []KeyRequest) ([]Result, error) {
+	for _, request := range requests {
+		if request.Bucket.DB != db {
+			return nil, fmt.Errorf("Bucket %s belongs to a different DB", request.Bucket.Name)
+		}
+	}
+
+	results := make([]Result, len(requests))
+
+	err := db.view(nil, func(tx *bolt.Tx) error {
+		for idx, request := range requests {
+			results[idx] = Result{}
+
+			bucket, err := request.Bucket.findPath(tx, request.Bucket.segments())
+			if err != nil {
+				results[idx].Err = err
+				continue
+			}
+
+			results[idx].Items, results[idx].Err = getMany(bucket, request.Keys)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// getMany retrieves copies of the values of the given keys from bolt.Bucket `bucket`
+func getMany(bucket *bolt.Bucket, keys [][]byte) ([]Item, error) {
+	var items []Item
+	var missing [][]byte
+
+	for _, key := range keys {
+		v := bucket.Get(key)
+		if v == nil {
+			missing = append(missing, key)
+			continue
+		}
+
+		value := make([]byte, len(v))
+		copy(value, v)
+		items = append(items, Item{key, value})
+	}
+
+	if missing != nil {
+		return items, &MissingKeysError{Keys: missing}
+	}
+
+	return items, nil
+}
+
+// Exists reports whether the given key is present in the bolt.Bucket specified by this Bucket without copying its value.
+// Keys of nested bolt.Buckets are not considered present, same as Get.
+func (b *Bucket) Exists(key []byte) (exists bool, err error) {
+	if b.withIndexedKeys(func(keys [][]byte) {
+		idx := searchKeys(keys, key)
+		exists = idx < len(keys) && bytes.Equal(keys[idx], key)
+	}) {
+		return exists, nil
+	}
+
+	err = b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		exists = bucket.Get(key) != nil
+		return nil
+	})
+
+	return exists, err
+}
+
+// ExistsString is a convenience wrapper over Exists for string key
+func (b *Bucket) ExistsString(key string) (bool, error) {
+	return b.Exists([]byte(key))
+}
+
 // GetAll retrieves all the key/value pairs from the bolt.Bucket specified by this Bucket
 func (b *Bucket) GetAll() ([]Item, error) {
 	var items []Item
@@ -374,6 +1004,7 @@ func (b *Bucket) GetAll() ([]Item, error) {
 		return nil
 	})
 
+	b.sortItems(items)
 	return items, err
 }
 
@@ -404,6 +1035,7 @@ func (b *Bucket) GetPrefix(prefix []byte) ([]Item, error) {
 		return nil
 	})
 
+	b.sortItems(items)
 	return items, err
 }
 
@@ -420,6 +1052,53 @@ func (b *Bucket) GetPrefixString(prefix string) (map[string]string, error) {
 	return items, err
 }
 
+// GetAllValues retrieves all the values from the bolt.Bucket specified by this Bucket in key order, without their keys
+func (b *Bucket) GetAllValues() ([][]byte, error) {
+	var values [][]byte
+	err := b.Map(func(k, v []byte) error {
+		if v != nil {
+			value := make([]byte, len(v))
+			copy(value, v)
+			values = append(values, value)
+		}
+		return nil
+	})
+
+	return values, err
+}
+
+// GetAllKeys retrieves all the keys from the bolt.Bucket specified by this Bucket in key order, without their values
+func (b *Bucket) GetAllKeys() ([][]byte, error) {
+	return b.GetKeysPrefix(nil)
+}
+
+// GetKeysPrefix retrieves all the keys from the bolt.Bucket specified by this Bucket with the given prefix in key order,
+// without their values
+func (b *Bucket) GetKeysPrefix(prefix []byte) ([][]byte, error) {
+	var keys [][]byte
+	if b.withIndexedKeys(func(indexed [][]byte) {
+		keys = copyNames(prefixKeys(indexed, prefix))
+	}) {
+		return keys, nil
+	}
+
+	return b.scanKeys(prefix)
+}
+
+func (b *Bucket) scanKeys(prefix []byte) ([][]byte, error) {
+	var keys [][]byte
+	err := b.MapPrefix(prefix, func(k, v []byte) error {
+		if v != nil {
+			key := make([]byte, len(k))
+			copy(key, k)
+			keys = append(keys, key)
+		}
+		return nil
+	})
+
+	return keys, err
+}
+
 // GetRange retrieves all the key/value pairs from the bolt.Bucket specified by this Bucket within the given range
 func (b *Bucket) GetRange(min, max []byte) ([]Item, error) {
 	var items []Item
@@ -435,6 +1114,7 @@ func (b *Bucket) GetRange(min, max []byte) ([]Item, error) {
 		return nil
 	})
 
+	b.sortItems(items)
 	return items, err
 }
 
@@ -452,10 +1132,52 @@ func (b *Bucket) GetRangeString(min, max string) (map[string]string, error) {
 	return items, err
 }
 
+// GetPrefixN retrieves at most `limit` key/value pairs from the bolt.Bucket specified by this Bucket with the given prefix,
+// skipping the first `offset` of them. The offset and limit apply in key order, and zero or less for `limit` means no limit.
+func (b *Bucket) GetPrefixN(prefix []byte, offset, limit int) ([]Item, error) {
+	var items []Item
+	err := b.MapPrefix(prefix, pageItems(offset, limit, &items))
+
+	b.sortItems(items)
+	return items, err
+}
+
+// GetRangeN retrieves at most `limit` key/value pairs from the bolt.Bucket specified by this Bucket within the given range,
+// skipping the first `offset` of them. The offset and limit apply in key order, and zero or less for `limit` means no limit.
+func (b *Bucket) GetRangeN(min, max []byte, offset, limit int) ([]Item, error) {
+	var items []Item
+	err := b.MapRange(min, max, pageItems(offset, limit, &items))
+
+	b.sortItems(items)
+	return items, err
+}
+
+// pageItems returns an iteration function appending copies of the visited key/value pairs to `items`,
+// skipping nested bolt.Buckets and the first `offset` pairs, and stopping the iteration once `limit` pairs are appended
+func pageItems(offset, limit int, items *[]Item) func([]byte, []byte) error {
+	return func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+
+		if offset > 0 {
+			offset--
+			return nil
+		}
+
+		*items = append(*items, Item{copyKey(k), copyKey(v)})
+		if limit > 0 && len(*items) >= limit {
+			return ErrStop
+		}
+
+		return nil
+	}
+}
+
 // Delete removes the given key from the bolt.Bucket specified by this Bucket
 func (b *Bucket) Delete(key []byte) error {
-	return b.Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
-		return bucket.Delete(key)
+	return b.mutate(OpDelete, key, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return b.delete(bucket, key)
 	})
 }
 
@@ -464,30 +1186,158 @@ func (b *Bucket) DeleteString(key string) error {
 	return b.Delete([]byte(key))
 }
 
-// GetRootBucketNames returns all the top level bolt.Bucket names under the bolt.Bucket specified by this Bucket
-func (b *Bucket) GetRootBucketNames() ([][]byte, error) {
-	var names [][]byte
-
-	err := b.Map(func(key []byte, value []byte) error {
-		if value == nil {
-			name := make([]byte, len(key))
-			copy(name, key)
-			names = append(names, name)
+// DeleteAll removes the given keys from the bolt.Bucket specified by this Bucket in a single transaction
+func (b *Bucket) DeleteAll(keys [][]byte) error {
+	return b.mutate(OpDeleteAll, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		for _, key := range keys {
+			err := b.delete(bucket, key)
+			if err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+}
 
+// DeleteAllString is a convenience method to DeleteAll string keys
+func (b *Bucket) DeleteAllString(keys []string) error {
+	return b.mutate(OpDeleteAll, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		for _, key := range keys {
+			err := b.delete(bucket, []byte(key))
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	})
+}
+
+// DeletePrefix removes all the keys with the given prefix from the bolt.Bucket specified by this Bucket in a single transaction,
+// and returns the number of keys removed. Nested bolt.Buckets are not removed.
+func (b *Bucket) DeletePrefix(prefix []byte) (count int, err error) {
+	err = b.mutate(OpDeletePrefix, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		count, err = b.deleteFrom(bucket, b.segments(), prefix, func(k []byte) bool {
+			return bytes.HasPrefix(k, prefix)
+		})
+		return err
+	})
+
+	return count, err
+}
+
+// DeleteRange removes all the keys within the given range from the bolt.Bucket specified by this Bucket in a single transaction,
+// and returns the number of keys removed. Nested bolt.Buckets are not removed.
+func (b *Bucket) DeleteRange(min, max []byte) (count int, err error) {
+	err = b.mutate(OpDeleteRange, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		count, err = b.deleteFrom(bucket, b.segments(), min, func(k []byte) bool {
+			return bytes.Compare(k, max) <= 0
+		})
+		return err
+	})
+
+	return count, err
+}
+
+// Clear removes all the key/value pairs from the bolt.Bucket specified by this Bucket in a single transaction,
+// keeping the nested bolt.Buckets and their contents
+func (b *Bucket) Clear() error {
+	return b.mutate(OpClear, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return b.clearBucket(bucket, b.segments(), false)
+	})
+}
+
+// ClearRecursive removes all the key/value pairs from the bolt.Bucket specified by this Bucket
+// and from all the bolt.Buckets nested under it in a single transaction, keeping the nested bolt.Buckets themselves
+func (b *Bucket) ClearRecursive() error {
+	return b.mutate(OpClear, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return b.clearBucket(bucket, b.segments(), true)
+	})
+}
+
+// clearBucket removes all the key/value pairs from bolt.Bucket `bucket` with the given path,
+// and from all the bolt.Buckets nested under it if `recursive` is set
+func (b *Bucket) clearBucket(bucket *bolt.Bucket, buckets [][]byte, recursive bool) error {
+	_, err := b.deleteFrom(bucket, buckets, nil, func([]byte) bool {
+		return true
+	})
+
+	if err != nil || !recursive {
+		return err
+	}
+
+	return bucket.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return nil
+		}
+
+		path := append(buckets[:len(buckets):len(buckets)], k)
+		return b.clearBucket(bucket.Bucket(k), path, true)
+	})
+}
+
+// deleteFrom removes the keys starting at `start` for which function `match` holds, stopping at the first key that does not match,
+// from bolt.Bucket `bucket` with the given path. Nothing is removed if the bolt.Bucket is write-once.
+func (b *Bucket) deleteFrom(bucket *bolt.Bucket, buckets [][]byte, start []byte, match func([]byte) bool) (int, error) {
+	var keys [][]byte
+
+	cursor := bucket.Cursor()
+	for k, v := cursor.Seek(start); k != nil && match(k); k, v = cursor.Next() {
+		if v != nil {
+			key := make([]byte, len(k))
+			copy(key, k)
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) > 0 && isWriteOnce(bucket.Tx(), buckets) {
+		return 0, writeOnceError(b.DB.joinNames(buckets, b.Separator), keys[0])
+	}
+
+	for _, key := range keys {
+		err := bucket.Delete(key)
+		if err != nil {
+			return 0, err
+		}
+
+		b.DB.recordKeyChange(bucket.Tx(), buckets, key, false)
+	}
+
+	b.DB.countDeletes(bucket.Tx(), len(keys))
+	return len(keys), nil
+}
+
+// Take retrieves the value for the given key and removes the key from the bolt.Bucket specified by this Bucket in a single transaction
+func (b *Bucket) Take(key []byte) (value []byte, err error) {
+	err = b.mutate(OpTake, key, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		v := bucket.Get(key)
+		if v == nil {
+			return keyNotFound(key)
+		}
+
+		value = make([]byte, len(v))
+		copy(value, v)
+		return b.delete(bucket, key)
+	})
+
+	return value, err
+}
+
+// TakeString is a convenience wrapper over Take for string key value pair
+func (b *Bucket) TakeString(key string) (string, error) {
+	value, err := b.Take([]byte(key))
+	return string(value), err
+}
 
+// GetRootBucketNames returns all the top level bolt.Bucket names under the bolt.Bucket specified by this Bucket
+func (b *Bucket) GetRootBucketNames() ([][]byte, error) {
+	names, err := b.childNames()
 	if err != nil {
 		return nil, err
 	}
 
 	bucketNames := make([][]byte, 0, len(names))
 	for _, name := range names {
-		bucketName := bytes.NewBuffer(b.Name)
-		bucketName.Write(b.Separator)
-		bucketName.Write(name)
-		bucketNames = append(bucketNames, bucketName.Bytes())
+		bucketNames = append(bucketNames, b.child(name).Name)
 	}
 
 	return bucketNames, nil
@@ -497,30 +1347,76 @@ func (b *Bucket) GetRootBucketNames() ([][]byte, error) {
 func (b *Bucket) GetAllBucketNames() ([][]byte, error) {
 	var allBucketNames [][]byte
 
-	bucketNames, err := b.GetRootBucketNames()
+	names, err := b.childNames()
 	if err != nil {
 		return nil, err
 	}
 
-	numBucketsToProcess := len(bucketNames)
-	var bucketName []byte
+	var bucketsToProcess []*Bucket
+	for _, name := range names {
+		bucketsToProcess = append(bucketsToProcess, b.child(name))
+	}
 
-	for numBucketsToProcess > 0 {
-		bucketName, bucketNames = bucketNames[0], bucketNames[1:]
-		numBucketsToProcess--
-		allBucketNames = append(allBucketNames, bucketName)
+	var bucket *Bucket
 
-		bucket := b.DB.Bucket(bucketName).WithSeparator(b.Separator)
-		subBucketNames, err := bucket.GetRootBucketNames()
+	for len(bucketsToProcess) > 0 {
+		bucket, bucketsToProcess = bucketsToProcess[0], bucketsToProcess[1:]
+		allBucketNames = append(allBucketNames, bucket.Name)
+
+		subBucketNames, err := bucket.childNames()
 		if err != nil {
 			return allBucketNames, err
 		}
 
-		if subBucketNames != nil && len(subBucketNames) > 0 {
-			numBucketsToProcess = numBucketsToProcess + len(subBucketNames)
-			bucketNames = append(bucketNames, subBucketNames...)
+		for _, name := range subBucketNames {
+			bucketsToProcess = append(bucketsToProcess, bucket.child(name))
 		}
 	}
 
 	return allBucketNames, nil
 }
+
+// childNames returns the names of the bolt.Buckets directly under the bolt.Bucket specified by this Bucket
+func (b *Bucket) childNames() ([][]byte, error) {
+	var names [][]byte
+
+	err := b.Map(func(key []byte, value []byte) error {
+		if value == nil {
+			name := make([]byte, len(key))
+			copy(name, key)
+			names = append(names, name)
+		}
+
+		return nil
+	})
+
+	return names, err
+}
+
+// Child returns a pointer to the Bucket for the bolt.Bucket with the given name directly under this Bucket,
+// bound to the same Tx as this Bucket
+func (b *Bucket) Child(name []byte) *Bucket {
+	return b.child(name)
+}
+
+// ChildString is a convenience wrapper over Child for string name
+func (b *Bucket) ChildString(name string) *Bucket {
+	return b.child([]byte(name))
+}
+
+// child returns a pointer to the Bucket for the bolt.Bucket with the given name directly under this Bucket
+func (b *Bucket) child(name []byte) *Bucket {
+	listed := b.DB.listedName(name, b.Separator)
+
+	bucketName := make([]byte, 0, len(b.Name)+len(b.Separator)+len(listed))
+	bucketName = append(append(append(bucketName, b.Name...), b.Separator...), listed...)
+
+	return &Bucket{
+		DB:        b.DB,
+		Name:      bucketName,
+		Separator: b.Separator,
+		path:      append(b.segments(), name),
+		explicit:  b.explicit,
+		tx:        b.tx,
+	}
+}
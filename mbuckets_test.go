@@ -2,12 +2,16 @@ package mbuckets_test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
 )
 
 type TestDB struct {
@@ -711,6 +715,95 @@ func TestGetRangeString(t *testing.T) {
 	}
 }
 
+func TestGetPrefixN(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting items")
+	err = bucket.InsertAllString(map[string]string{"a1": "1", "a2": "2", "a3": "3", "a4": "4", "b1": "5"})
+	if err != nil {
+		t.Errorf("Unable to insert items in bucket. Error: %s", err.Error())
+	}
+
+	err = bucket.ChildString("a0").CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create nested bucket. Error: %s", err.Error())
+	}
+
+	tests := []struct {
+		offset, limit int
+		keys          string
+	}{
+		{0, 0, "a1a2a3a4"},
+		{0, 2, "a1a2"},
+		{1, 2, "a2a3"},
+		{3, 2, "a4"},
+		{5, 2, ""},
+	}
+
+	for _, test := range tests {
+		t.Logf("Retrieving items with prefix a, offset %d and limit %d", test.offset, test.limit)
+		results, err := bucket.GetPrefixN([]byte("a"), test.offset, test.limit)
+		if err != nil {
+			t.Errorf("Unable to retrieve items with prefix from bucket. Error: %s", err.Error())
+		}
+
+		keys := ""
+		for _, item := range results {
+			keys += string(item.Key)
+		}
+
+		if keys != test.keys {
+			t.Errorf("Retrieved keys %s do not match the expected keys %s", keys, test.keys)
+		}
+	}
+
+	t.Log("Retrieving items with the ScanContinue policy")
+	results, err := bucket.WithScanErrorPolicy(mbuckets.ScanContinue).GetPrefixN([]byte("a"), 1, 1)
+	if err != nil || len(results) != 1 || string(results[0].Key) != "a2" {
+		t.Errorf("Retrieved items %v do not match with the ScanContinue policy. Error: %v", results, err)
+	}
+}
+
+func TestGetRangeN(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting items")
+	err = bucket.InsertAllString(map[string]string{"key1": "1", "key2": "2", "key3": "3", "key4": "4", "key5": "5"})
+	if err != nil {
+		t.Errorf("Unable to insert items in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving items in range key2 - key5 with offset 1 and limit 2")
+	results, err := bucket.GetRangeN([]byte("key2"), []byte("key5"), 1, 2)
+	if err != nil {
+		t.Errorf("Unable to retrieve items for given range from bucket. Error: %s", err.Error())
+	}
+
+	for idx, item := range results {
+		t.Logf("Item %d: Key = %s, Value = %s", idx, item.Key, item.Value)
+	}
+
+	if len(results) != 2 || string(results[0].Key) != "key3" || string(results[1].Key) != "key4" {
+		t.Error("Items retrieved from bucket in given range do not match the offset and limit")
+	}
+}
+
 func TestDelete(t *testing.T) {
 	t.Log("Creating a new test db")
 	db, err := NewTestDB()
@@ -1178,7 +1271,7 @@ func TestGetRootBucketNamesUnderBucket(t *testing.T) {
 		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
 	}
 
-	t.Logf("Retrieving root bucket names under bucket: %s", bucket1)
+	t.Logf("Retrieving root bucket names under bucket: %s", bucketName1)
 	bucketNames, err := bucket1.GetRootBucketNames()
 	if err != nil {
 		t.Errorf("Unable to get root bucket names under bucket: %s. Error: %s", bucketName1, err.Error())
@@ -1320,3 +1413,943 @@ func TestGetAllBucketNamesFromDB(t *testing.T) {
 		t.Error("Not all buckets retrieved from db match the ones created")
 	}
 }
+
+func TestTake(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	key := "key1"
+	value := "value1"
+
+	t.Logf("Inserting Key: %s with Value: %s in bucket: %s", key, value, bucketName)
+	err = bucket.InsertString(key, value)
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	t.Logf("Taking Value for Key: %s from bucket: %s", key, bucketName)
+	result, err := bucket.TakeString(key)
+	if err != nil {
+		t.Errorf("Unable to take value for given key from bucket. Error: %s", err.Error())
+	}
+
+	if result != value {
+		t.Error("Value taken does not match value set for the same key in bucket")
+	}
+
+	t.Logf("Taking Value for Key: %s again from bucket: %s", key, bucketName)
+	_, err = bucket.TakeString(key)
+	if err == nil {
+		t.Error("Key was not removed from bucket when its value was taken")
+	}
+}
+
+func TestUpdateKeys(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{"from": "100", "to": "0"}
+
+	t.Log("Inserting key/value pairs")
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	keys := [][]byte{[]byte("from"), []byte("to"), []byte("missing")}
+
+	t.Log("Updating keys")
+	err = bucket.UpdateKeys(keys, func(key, old []byte) ([]byte, error) {
+		switch string(key) {
+		case "from":
+			return []byte("60"), nil
+		case "to":
+			return append(old, []byte("+40")...), nil
+		}
+
+		if old != nil {
+			t.Errorf("Old value passed for missing key: %s", key)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Errorf("Unable to update keys in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Updating keys with a failing function")
+	err = bucket.UpdateKeys(keys, func(key, old []byte) ([]byte, error) {
+		if string(key) == "to" {
+			return nil, fmt.Errorf("Simulated failure")
+		}
+		return []byte("lost"), nil
+	})
+	if err == nil {
+		t.Error("Error returned by update function was not returned")
+	}
+
+	results, err := bucket.GetAllString()
+	if err != nil {
+		t.Errorf("Unable to retrieve key/value pairs from bucket. Error: %s", err.Error())
+	}
+
+	expected := map[string]string{"from": "60", "to": "0+40"}
+	if fmt.Sprint(results) != fmt.Sprint(expected) {
+		t.Errorf("Key/value pairs %v do not match the expected key/value pairs %v", results, expected)
+	}
+}
+
+func TestClosedDB(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Closing the test db")
+	err = db.DB.Close()
+	if err != nil {
+		t.Errorf("Unable to close the test db. Error: %s", err.Error())
+	}
+
+	if !db.IsClosed() {
+		t.Error("Test db is not reported as closed")
+	}
+
+	t.Log("Inserting a key/value pair in closed db")
+	err = bucket.InsertString("key1", "value1")
+	if !errors.Is(err, mbuckets.ErrDBClosed) {
+		t.Errorf("Insert in closed db did not return ErrDBClosed. Error: %v", err)
+	}
+
+	t.Log("Retrieving a key/value pair from closed db")
+	_, err = bucket.GetString("key1")
+	if !errors.Is(err, mbuckets.ErrDBClosed) {
+		t.Errorf("Get from closed db did not return ErrDBClosed. Error: %v", err)
+	}
+
+	t.Log("Retrieving all bucket names from closed db")
+	_, err = db.GetAllBucketNames()
+	if !errors.Is(err, mbuckets.ErrDBClosed) {
+		t.Errorf("GetAllBucketNames from closed db did not return ErrDBClosed. Error: %v", err)
+	}
+}
+
+func TestNotFoundErrors(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving a missing key")
+	_, err = bucket.Get([]byte("missing"))
+	if !errors.Is(err, mbuckets.ErrKeyNotFound) {
+		t.Errorf("Get of missing key did not return ErrKeyNotFound. Error: %v", err)
+	}
+
+	_, err = bucket.GetString("missing")
+	if !errors.Is(err, mbuckets.ErrKeyNotFound) {
+		t.Errorf("GetString of missing key did not return ErrKeyNotFound. Error: %v", err)
+	}
+
+	for _, name := range []string{"Missing", "Bucket1/Missing", "Missing/Bucket"} {
+		t.Logf("Accessing missing bucket: %s", name)
+		missing := db.BucketString(name)
+
+		_, err = missing.GetString("key1")
+		if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+			t.Errorf("Get from missing bucket did not return ErrBucketNotFound. Error: %v", err)
+		}
+
+		err = missing.DeleteBucket()
+		if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+			t.Errorf("Deletion of missing bucket did not return ErrBucketNotFound. Error: %v", err)
+		}
+	}
+}
+
+func TestExists(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	key := "key1"
+	value := "value1"
+
+	t.Logf("Inserting Key: %s with Value: %s in bucket: %s", key, value, bucketName)
+	err = bucket.InsertString(key, value)
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	subBucketName := []byte("Bucket1/Bucket2")
+	t.Logf("Creating nested bucket: %s", subBucketName)
+	err = db.Bucket(subBucketName).CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create nested bucket. Error: %s", err.Error())
+	}
+
+	for key, expected := range map[string]bool{"key1": true, "missing": false, "Bucket2": false} {
+		t.Logf("Checking existence of Key: %s in bucket: %s", key, bucketName)
+		exists, err := bucket.ExistsString(key)
+		if err != nil {
+			t.Errorf("Unable to check existence of key in bucket. Error: %s", err.Error())
+		}
+
+		if exists != expected {
+			t.Errorf("Existence of key %s reported as %t, expected %t", key, exists, expected)
+		}
+	}
+
+	t.Log("Checking existence of a key in a missing bucket")
+	_, err = db.BucketString("Missing").ExistsString(key)
+	if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+		t.Errorf("Existence check in missing bucket did not return ErrBucketNotFound. Error: %v", err)
+	}
+}
+
+func TestBucketExists(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1/Bucket2")
+	t.Logf("Creating bucket: %s", bucketName)
+	err = db.Bucket(bucketName).CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create bucket. Error: %s", err.Error())
+	}
+
+	for name, expected := range map[string]bool{"Bucket1": true, "Bucket1/Bucket2": true, "Bucket1/Missing": false, "Missing/Bucket2": false} {
+		t.Logf("Checking existence of bucket: %s", name)
+		exists, err := db.BucketExists([]byte(name))
+		if err != nil {
+			t.Errorf("Unable to check existence of bucket. Error: %s", err.Error())
+		}
+
+		if exists != expected {
+			t.Errorf("Existence of bucket %s reported as %t, expected %t", name, exists, expected)
+		}
+	}
+
+	t.Log("Verifying that checking existence does not create the bucket")
+	exists, err := db.BucketString("Bucket1/Missing").BucketExists()
+	if err != nil || exists {
+		t.Error("Checking existence of a missing bucket created it")
+	}
+
+	t.Log("Checking existence of a bucket in a closed db")
+	db.DB.Close()
+	_, err = db.BucketExists(bucketName)
+	if !errors.Is(err, mbuckets.ErrDBClosed) {
+		t.Errorf("Existence check in closed db did not return ErrDBClosed. Error: %v", err)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{"key1": "value1", "key2": "value2", "key3": "value3"}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving existing keys")
+	result, err := bucket.GetMany([][]byte{[]byte("key3"), []byte("key1")})
+	if err != nil {
+		t.Errorf("Unable to retrieve keys from bucket. Error: %s", err.Error())
+	}
+
+	if len(result) != 2 || string(result[0].Key) != "key3" || string(result[1].Value) != "value1" {
+		t.Errorf("Retrieved items %s do not match the requested keys", result)
+	}
+
+	t.Log("Retrieving existing and missing keys")
+	resultString, err := bucket.GetManyString([]string{"key1", "missing1", "key2", "missing2"})
+
+	var missingErr *mbuckets.MissingKeysError
+	if !errors.As(err, &missingErr) || !errors.Is(err, mbuckets.ErrKeyNotFound) {
+		t.Fatalf("Retrieval of missing keys did not return a MissingKeysError. Error: %v", err)
+	}
+
+	if fmt.Sprintf("%s", missingErr.Keys) != "[missing1 missing2]" {
+		t.Errorf("Missing keys %s do not match the keys not inserted", missingErr.Keys)
+	}
+
+	if len(resultString) != 2 || resultString["key1"] != "value1" || resultString["key2"] != "value2" {
+		t.Errorf("Retrieved items %v do not match the existing keys", resultString)
+	}
+}
+
+func TestDeletePrefix(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{"a1": "value1", "a2": "value2", "b1": "value3", "b2": "value4", "c1": "value5"}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	subBucketName := []byte("Bucket1/b3")
+	t.Logf("Creating nested bucket: %s", subBucketName)
+	err = db.Bucket(subBucketName).CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create nested bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Deleting keys with prefix: b")
+	count, err := bucket.DeletePrefix([]byte("b"))
+	if err != nil {
+		t.Errorf("Unable to delete keys with prefix from bucket. Error: %s", err.Error())
+	}
+
+	if count != 2 {
+		t.Errorf("Number of keys deleted %d does not match the number of keys with prefix", count)
+	}
+
+	result, err := bucket.GetAllString()
+	if err != nil {
+		t.Errorf("Unable to retrieve key/value pairs from bucket. Error: %s", err.Error())
+	}
+
+	if len(result) != 3 || result["a1"] != "value1" || result["c1"] != "value5" {
+		t.Errorf("Remaining key/value pairs %v do not match the keys without prefix", result)
+	}
+
+	exists, err := db.BucketExists(subBucketName)
+	if err != nil || !exists {
+		t.Error("Nested bucket with prefix was deleted")
+	}
+}
+
+func TestDeleteRange(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := make(map[string]string, 10)
+	for i := 0; i < 10; i++ {
+		items[fmt.Sprintf("key%d", i)] = fmt.Sprintf("value%d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Deleting keys in range: key2 to key5")
+	count, err := bucket.DeleteRange([]byte("key2"), []byte("key5"))
+	if err != nil {
+		t.Errorf("Unable to delete keys in range from bucket. Error: %s", err.Error())
+	}
+
+	if count != 4 {
+		t.Errorf("Number of keys deleted %d does not match the number of keys in range", count)
+	}
+
+	result, err := bucket.GetAllString()
+	if err != nil {
+		t.Errorf("Unable to retrieve key/value pairs from bucket. Error: %s", err.Error())
+	}
+
+	for key := range items {
+		_, ok := result[key]
+		if inRange := key >= "key2" && key <= "key5"; ok == inRange {
+			t.Errorf("Key %s in range: %t, remaining after deletion: %t", key, inRange, ok)
+		}
+	}
+}
+
+func TestClear(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	items := map[string]string{"key1": "value1", "key2": "value2"}
+
+	for _, name := range []string{"Bucket1", "Bucket1/Bucket2", "Bucket1/Bucket2/Bucket3"} {
+		t.Logf("Inserting %d key/value pairs in bucket: %s", len(items), name)
+		err = db.BucketString(name).InsertAllString(items)
+		if err != nil {
+			t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+		}
+	}
+
+	count := func(name string) int {
+		result, err := db.BucketString(name).GetAllString()
+		if err != nil {
+			t.Errorf("Unable to retrieve key/value pairs from bucket %s. Error: %s", name, err.Error())
+		}
+		return len(result)
+	}
+
+	t.Log("Clearing bucket: Bucket1")
+	err = db.BucketString("Bucket1").Clear()
+	if err != nil {
+		t.Errorf("Unable to clear bucket. Error: %s", err.Error())
+	}
+
+	if count("Bucket1") != 0 || count("Bucket1/Bucket2") != len(items) || count("Bucket1/Bucket2/Bucket3") != len(items) {
+		t.Error("Clear did not remove exactly the key/value pairs of the bucket")
+	}
+
+	t.Log("Recursively clearing bucket: Bucket1")
+	err = db.BucketString("Bucket1").ClearRecursive()
+	if err != nil {
+		t.Errorf("Unable to recursively clear bucket. Error: %s", err.Error())
+	}
+
+	if count("Bucket1/Bucket2") != 0 || count("Bucket1/Bucket2/Bucket3") != 0 {
+		t.Error("ClearRecursive did not remove the key/value pairs of the nested buckets")
+	}
+
+	names, err := db.GetAllBucketNames()
+	if err != nil || len(names) != 3 {
+		t.Errorf("Nested buckets %s were not kept when clearing", names)
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{"key1": "value1", "key2": "value2", "key3": "value3", "key4": "value4"}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Deleting keys: key1, missing")
+	err = bucket.DeleteAll([][]byte{[]byte("key1"), []byte("missing")})
+	if err != nil {
+		t.Errorf("Unable to delete keys from bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Deleting keys: key2, key3")
+	err = bucket.DeleteAllString([]string{"key2", "key3"})
+	if err != nil {
+		t.Errorf("Unable to delete keys from bucket. Error: %s", err.Error())
+	}
+
+	result, err := bucket.GetAllString()
+	if err != nil {
+		t.Errorf("Unable to retrieve key/value pairs from bucket. Error: %s", err.Error())
+	}
+
+	if len(result) != 1 || result["key4"] != "value4" {
+		t.Errorf("Remaining key/value pairs %v do not match the keys not deleted", result)
+	}
+}
+
+func TestInsertIfAbsent(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	key := "key1"
+
+	t.Logf("Inserting Key: %s if absent in bucket: %s", key, bucketName)
+	inserted, err := bucket.InsertIfAbsentString(key, "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	if !inserted {
+		t.Error("Absent key was not inserted")
+	}
+
+	t.Logf("Inserting Key: %s if absent again in bucket: %s", key, bucketName)
+	inserted, err = bucket.InsertIfAbsentString(key, "value2")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	if inserted {
+		t.Error("Existing key was reported as inserted")
+	}
+
+	value, err := bucket.GetString(key)
+	if err != nil || value != "value1" {
+		t.Errorf("Value %s of existing key was overwritten", value)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	key := []byte("key1")
+
+	steps := []struct {
+		old, value []byte
+		swapped    bool
+	}{
+		{nil, []byte("value1"), true},
+		{nil, []byte("value2"), false},
+		{[]byte("value2"), []byte("value3"), false},
+		{[]byte("value1"), []byte("value3"), true},
+		{[]byte{}, []byte("value4"), false},
+	}
+
+	for _, step := range steps {
+		t.Logf("Swapping Value: %q with Value: %s for Key: %s", step.old, step.value, key)
+		swapped, err := bucket.CompareAndSwap(key, step.old, step.value)
+		if err != nil {
+			t.Errorf("Unable to compare and swap value in bucket. Error: %s", err.Error())
+		}
+
+		if swapped != step.swapped {
+			t.Errorf("Swap reported as %t, expected %t", swapped, step.swapped)
+		}
+	}
+
+	value, err := bucket.Get(key)
+	if err != nil || string(value) != "value3" {
+		t.Errorf("Value %s does not match the last value swapped in", value)
+	}
+}
+
+func TestGetAllKeys(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{"a1": "value1", "a2": "value2", "b1": "value3"}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	err = db.BucketString("Bucket1/a3").CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create nested bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving all keys")
+	keys, err := bucket.GetAllKeys()
+	if err != nil {
+		t.Errorf("Unable to retrieve keys from bucket. Error: %s", err.Error())
+	}
+
+	if fmt.Sprintf("%s", keys) != "[a1 a2 b1]" {
+		t.Errorf("Keys %s do not match the keys inserted", keys)
+	}
+
+	t.Log("Retrieving keys with prefix: a")
+	keys, err = bucket.GetKeysPrefix([]byte("a"))
+	if err != nil {
+		t.Errorf("Unable to retrieve keys with prefix from bucket. Error: %s", err.Error())
+	}
+
+	if fmt.Sprintf("%s", keys) != "[a1 a2]" {
+		t.Errorf("Keys %s do not match the keys inserted with prefix", keys)
+	}
+}
+
+func TestGetAllValues(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{"key1": "value1", "key2": "value2", "key3": "value3"}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	err = db.BucketString("Bucket1/key4").CreateBucket()
+	if err != nil {
+		t.Errorf("Unable to create nested bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving all values")
+	values, err := bucket.GetAllValues()
+	if err != nil {
+		t.Errorf("Unable to retrieve values from bucket. Error: %s", err.Error())
+	}
+
+	if fmt.Sprintf("%s", values) != "[value1 value2 value3]" {
+		t.Errorf("Values %s do not match the values inserted", values)
+	}
+}
+
+func TestBucketAt(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketAtString("Bucket1", "2024/01", "a:b")
+	t.Logf("Creating bucket with segments: %s", bucket.Name)
+
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		parent := tx.Bucket([]byte("Bucket1")).Bucket([]byte("2024/01"))
+		if parent == nil || parent.Bucket([]byte("a:b")) == nil {
+			t.Error("Segments containing the separator were split")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Unable to view db. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving value through a Bucket with the same segments")
+	value, err := db.BucketAtString("Bucket1", "2024/01", "a:b").GetString("key1")
+	if err != nil || value != "value1" {
+		t.Errorf("Value %s does not match the value inserted. Error: %v", value, err)
+	}
+
+	t.Log("Retrieving all bucket names under segments containing the separator")
+	names, err := db.BucketAtString("Bucket1").GetAllBucketNames()
+	if err != nil {
+		t.Errorf("Unable to retrieve bucket names. Error: %s", err.Error())
+	}
+
+	if fmt.Sprintf("%s", names) != "[Bucket1/2024/01 Bucket1/2024/01/a:b]" {
+		t.Errorf("Bucket names %s do not match the buckets created", names)
+	}
+
+	exists, err := db.BucketExists([]byte("Bucket1/2024"))
+	if err != nil || exists {
+		t.Error("Bucket was created for a part of a segment")
+	}
+}
+
+func TestStrict(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1/Bucket2").Strict()
+
+	t.Logf("Inserting key/value in missing bucket in strict mode: %s", bucket.Name)
+	err = bucket.InsertString("key1", "value1")
+	if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+		t.Errorf("Insert in missing bucket in strict mode did not return ErrBucketNotFound. Error: %v", err)
+	}
+
+	exists, err := db.BucketExists([]byte("Bucket1"))
+	if err != nil || exists {
+		t.Error("Insert in strict mode created a bucket")
+	}
+
+	t.Logf("Ensuring path of bucket: %s", bucket.Name)
+	err = bucket.EnsurePath()
+	if err != nil {
+		t.Errorf("Unable to ensure path of bucket. Error: %s", err.Error())
+	}
+
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in existing bucket in strict mode. Error: %s", err.Error())
+	}
+
+	t.Log("Enabling strict mode for the db")
+	db.SetStrict(true)
+
+	err = db.BucketString("Bucket1/Missing").InsertString("key1", "value1")
+	if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+		t.Errorf("Insert in missing bucket with strict db did not return ErrBucketNotFound. Error: %v", err)
+	}
+
+	t.Log("Disabling strict mode for the db")
+	db.SetStrict(false)
+
+	err = db.BucketString("Bucket1/Missing").InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in missing bucket outside strict mode. Error: %s", err.Error())
+	}
+}
+
+func TestGetManyAcross(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	for _, name := range []string{"Users", "Settings/Theme"} {
+		t.Logf("Inserting key/value pairs in bucket: %s", name)
+		err = db.BucketString(name).InsertAllString(map[string]string{"key1": name + "1", "key2": name + "2"})
+		if err != nil {
+			t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+		}
+	}
+
+	requests := []mbuckets.KeyRequest{
+		{Bucket: db.BucketString("Users"), Keys: [][]byte{[]byte("key2")}},
+		{Bucket: db.BucketString("Settings/Theme"), Keys: [][]byte{[]byte("key1"), []byte("missing")}},
+		{Bucket: db.BucketString("Missing"), Keys: [][]byte{[]byte("key1")}},
+	}
+
+	t.Log("Retrieving keys across buckets")
+	results, err := db.GetManyAcross(requests)
+	if err != nil {
+		t.Fatalf("Unable to retrieve keys across buckets. Error: %s", err.Error())
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Number of results %d does not match the number of requests", len(results))
+	}
+
+	if results[0].Err != nil || len(results[0].Items) != 1 || string(results[0].Items[0].Value) != "Users2" {
+		t.Errorf("Result %v does not match the first request", results[0])
+	}
+
+	var missingErr *mbuckets.MissingKeysError
+	if !errors.As(results[1].Err, &missingErr) || len(results[1].Items) != 1 || string(results[1].Items[0].Value) != "Settings/Theme1" {
+		t.Errorf("Result %v does not match the second request", results[1])
+	}
+
+	if !errors.Is(results[2].Err, mbuckets.ErrBucketNotFound) {
+		t.Errorf("Result for missing bucket did not report ErrBucketNotFound. Error: %v", results[2].Err)
+	}
+}
+
+func TestWithFillPercent(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	items := make([]mbuckets.Item, 2000)
+	for idx := range items {
+		items[idx] = mbuckets.Item{Key: mbuckets.Uint64Key(uint64(idx)), Value: []byte("value")}
+	}
+
+	leafPages := func(name string) int {
+		var pages int
+		err := db.View(func(tx *bolt.Tx) error {
+			pages = tx.Bucket([]byte(name)).Stats().LeafPageN
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Unable to read stats of bucket %s. Error: %s", name, err.Error())
+		}
+		return pages
+	}
+
+	t.Log("Inserting items with the default fill percent")
+	err = db.BucketString("Default").InsertAll(items)
+	if err != nil {
+		t.Errorf("Unable to insert items. Error: %s", err.Error())
+	}
+
+	t.Log("Inserting items with a fill percent of 1.0")
+	err = db.BucketString("Packed").WithFillPercent(1.0).InsertAll(items)
+	if err != nil {
+		t.Errorf("Unable to insert items. Error: %s", err.Error())
+	}
+
+	defaultPages, packedPages := leafPages("Default"), leafPages("Packed")
+	t.Logf("Leaf pages with default fill percent: %d, with fill percent 1.0: %d", defaultPages, packedPages)
+
+	if packedPages >= defaultPages {
+		t.Errorf("Fill percent 1.0 did not reduce the number of leaf pages: %d >= %d", packedPages, defaultPages)
+	}
+}
+
+func TestInsertBatched(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Batched/Bucket")
+
+	t.Log("Inserting key/value pairs from 50 goroutines")
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- bucket.InsertBatchedString(fmt.Sprintf("key%02d", i), fmt.Sprintf("value%02d", i))
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+		}
+	}
+
+	count, err := bucket.Count()
+	if err != nil {
+		t.Errorf("Unable to count key/value pairs. Error: %s", err.Error())
+	}
+
+	if count != 50 {
+		t.Errorf("Number of key/value pairs %d does not match the number inserted", count)
+	}
+
+	t.Log("Updating in a batched transaction")
+	err = db.Batch(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("Batched")).Bucket([]byte("Bucket")).Put([]byte("key00"), []byte("batched"))
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update in batched transaction. Error: %s", err.Error())
+	}
+
+	value, err := bucket.GetString("key00")
+	if err != nil {
+		t.Errorf("Unable to retrieve value. Error: %s", err.Error())
+	}
+
+	if value != "batched" {
+		t.Errorf("Value %s does not match the value put in the batched transaction", value)
+	}
+}
@@ -0,0 +1,44 @@
+package mbuckets
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+// Name of the root bolt.Bucket holding the state of mbuckets itself.
+// It is hidden from the bucket listings of DB.
+var metaBucketName = []byte("__mbuckets__")
+
+// metaBucket returns the bolt.Bucket with the given name inside the meta bucket,
+// creating both when the transaction is writable. It returns nil if they do not exist.
+func metaBucket(tx *bolt.Tx, name []byte) (*bolt.Bucket, error) {
+	if !tx.Writable() {
+		meta := tx.Bucket(metaBucketName)
+		if meta == nil {
+			return nil, nil
+		}
+		return meta.Bucket(name), nil
+	}
+
+	meta, err := tx.CreateBucketIfNotExists(metaBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return meta.CreateBucketIfNotExists(name)
+}
+
+// checkReserved returns an error wrapping ErrReservedBucket if this Bucket specifies the meta bucket or a bolt.Bucket under it
+func (b *Bucket) checkReserved() error {
+	if buckets := b.segments(); len(buckets) > 0 && isMetaBucket(buckets[0]) {
+		return fmt.Errorf("%w: %s", ErrReservedBucket, b.Name)
+	}
+
+	return nil
+}
+
+func isMetaBucket(name []byte) bool {
+	return bytes.Equal(name, metaBucketName)
+}
@@ -0,0 +1,106 @@
+package mbuckets
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+// MoveKey moves the given key and its value from the bolt.Bucket specified by this Bucket
+// to the bolt.Bucket specified by Bucket `dst` in a single transaction, creating `dst` if needed outside strict mode.
+// An existing value of the key in `dst` is replaced.
+func (b *Bucket) MoveKey(key []byte, dst *Bucket) error {
+	if dst.DB != b.DB {
+		return fmt.Errorf("Bucket %s belongs to a different DB", dst.Name)
+	}
+
+	dstBuckets := dst.segments()
+	same := pathKey(b.segments()) == pathKey(dstBuckets)
+
+	b.DB.mu.RLock()
+	limits := b.DB.limits
+	b.DB.mu.RUnlock()
+
+	create := !dst.isStrict()
+
+	created := false
+
+	err := b.mutate(OpMoveKey, key, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		v := bucket.Get(key)
+		if v == nil {
+			return keyNotFound(key)
+		}
+
+		if same {
+			return nil
+		}
+
+		target, isNew, err := dst.createPath(tx, dstBuckets, limits, create)
+		if err != nil {
+			return err
+		}
+		created = isNew
+
+		err = dst.checkFence(tx, dstBuckets)
+		if err != nil {
+			return err
+		}
+
+		value := make([]byte, len(v))
+		copy(value, v)
+
+		err = dst.put(target, key, value)
+		if err != nil {
+			return err
+		}
+
+		return b.delete(bucket, key)
+	})
+
+	if err == nil && !same {
+		b.afterCommit(func() {
+			if created {
+				b.DB.structureChanged(dstBuckets, false)
+			}
+		})
+	}
+
+	return err
+}
+
+// MoveKeyString is a convenience wrapper over MoveKey for string key
+func (b *Bucket) MoveKeyString(key string, dst *Bucket) error {
+	return b.MoveKey([]byte(key), dst)
+}
+
+// RenameKey replaces key `oldKey` with key `newKey`, keeping its value,
+// in the bolt.Bucket specified by this Bucket in a single transaction.
+// An existing value of `newKey` is replaced.
+func (b *Bucket) RenameKey(oldKey, newKey []byte) error {
+	return b.mutate(OpRenameKey, oldKey, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		v := bucket.Get(oldKey)
+		if v == nil {
+			return keyNotFound(oldKey)
+		}
+
+		if bytes.Equal(oldKey, newKey) {
+			return nil
+		}
+
+		value := make([]byte, len(v))
+		copy(value, v)
+
+		err := b.put(bucket, newKey, value)
+		if err != nil {
+			return err
+		}
+
+		return b.delete(bucket, oldKey)
+	})
+}
+
+// RenameKeyString is a convenience wrapper over RenameKey for string keys
+func (b *Bucket) RenameKeyString(oldKey, newKey string) error {
+	return b.RenameKey([]byte(oldKey), []byte(newKey))
+}
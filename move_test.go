@@ -0,0 +1,93 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestMoveKey(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	src := db.BucketString("Bucket1")
+	dst := db.BucketString("Bucket2/Bucket3")
+
+	err = src.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	t.Logf("Moving Key: key1 from bucket: %s to bucket: %s", src.Name, dst.Name)
+	err = src.MoveKeyString("key1", dst)
+	if err != nil {
+		t.Errorf("Unable to move key between buckets. Error: %s", err.Error())
+	}
+
+	exists, _ := src.ExistsString("key1")
+	if exists {
+		t.Error("Moved key still exists in source bucket")
+	}
+
+	value, err := dst.GetString("key1")
+	if err != nil || value != "value1" {
+		t.Errorf("Moved value %s does not match the value inserted. Error: %v", value, err)
+	}
+
+	t.Log("Moving a missing key")
+	err = src.MoveKeyString("key1", dst)
+	if !errors.Is(err, mbuckets.ErrKeyNotFound) {
+		t.Errorf("Move of missing key did not return ErrKeyNotFound. Error: %v", err)
+	}
+
+	t.Log("Moving a key to its own bucket")
+	err = dst.MoveKeyString("key1", db.BucketString("Bucket2/Bucket3"))
+	if err != nil {
+		t.Errorf("Unable to move key to its own bucket. Error: %s", err.Error())
+	}
+
+	exists, _ = dst.ExistsString("key1")
+	if !exists {
+		t.Error("Key moved to its own bucket was removed")
+	}
+}
+
+func TestRenameKey(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	err = bucket.InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Renaming Key: key1 to Key: key2")
+	err = bucket.RenameKeyString("key1", "key2")
+	if err != nil {
+		t.Errorf("Unable to rename key. Error: %s", err.Error())
+	}
+
+	items, err := bucket.GetAllString()
+	if err != nil || len(items) != 1 || items["key2"] != "value1" {
+		t.Errorf("Key/value pairs %v do not match the renamed key. Error: %v", items, err)
+	}
+
+	t.Log("Renaming a missing key")
+	err = bucket.RenameKeyString("key1", "key3")
+	if !errors.Is(err, mbuckets.ErrKeyNotFound) {
+		t.Errorf("Rename of missing key did not return ErrKeyNotFound. Error: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package mbuckets
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Uint64Key encodes the given number as an 8 byte big-endian key, so that keys sort in numeric order
+func Uint64Key(n uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, n)
+	return key
+}
+
+// Int64Key encodes the given number as an 8 byte key with the sign bit flipped, so that keys sort in numeric order
+func Int64Key(n int64) []byte {
+	return Uint64Key(uint64(n) ^ (1 << 63))
+}
+
+// Float64Key encodes the given number as an 8 byte key so that keys sort in numeric order, with NaN sorting last
+func Float64Key(f float64) []byte {
+	bits := math.Float64bits(f)
+	if bits&(1<<63) != 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+
+	return Uint64Key(bits)
+}
+
+// ParseUint64Key decodes a key encoded by Uint64Key
+func ParseUint64Key(key []byte) (uint64, error) {
+	if len(key) != 8 {
+		return 0, fmt.Errorf("Invalid numeric key: %d bytes", len(key))
+	}
+
+	return binary.BigEndian.Uint64(key), nil
+}
+
+// ParseInt64Key decodes a key encoded by Int64Key
+func ParseInt64Key(key []byte) (int64, error) {
+	n, err := ParseUint64Key(key)
+	return int64(n ^ (1 << 63)), err
+}
+
+// ParseFloat64Key decodes a key encoded by Float64Key
+func ParseFloat64Key(key []byte) (float64, error) {
+	bits, err := ParseUint64Key(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if bits&(1<<63) != 0 {
+		bits &^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+
+	return math.Float64frombits(bits), nil
+}
+
+// InsertUint64 puts a single key/value pair with a numeric key encoded by Uint64Key in the bolt.Bucket specified by this Bucket
+func (b *Bucket) InsertUint64(key uint64, value []byte) error {
+	return b.Insert(Uint64Key(key), value)
+}
+
+// GetUint64 retrieves the value for a numeric key encoded by Uint64Key from the bolt.Bucket specified by this Bucket
+func (b *Bucket) GetUint64(key uint64) ([]byte, error) {
+	return b.Get(Uint64Key(key))
+}
+
+// GetRangeUint64 retrieves all the key/value pairs with numeric keys encoded by Uint64Key
+// within the given range from the bolt.Bucket specified by this Bucket
+func (b *Bucket) GetRangeUint64(min, max uint64) ([]Item, error) {
+	return b.GetRange(Uint64Key(min), Uint64Key(max))
+}
+
+// InsertInt64 puts a single key/value pair with a numeric key encoded by Int64Key in the bolt.Bucket specified by this Bucket
+func (b *Bucket) InsertInt64(key int64, value []byte) error {
+	return b.Insert(Int64Key(key), value)
+}
+
+// GetInt64 retrieves the value for a numeric key encoded by Int64Key from the bolt.Bucket specified by this Bucket
+func (b *Bucket) GetInt64(key int64) ([]byte, error) {
+	return b.Get(Int64Key(key))
+}
+
+// GetRangeInt64 retrieves all the key/value pairs with numeric keys encoded by Int64Key
+// within the given range from the bolt.Bucket specified by this Bucket
+func (b *Bucket) GetRangeInt64(min, max int64) ([]Item, error) {
+	return b.GetRange(Int64Key(min), Int64Key(max))
+}
@@ -0,0 +1,81 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestNumericKeys(t *testing.T) {
+	ints := []int64{math.MinInt64, -1000, -1, 0, 1, 255, 256, math.MaxInt64}
+	for idx := 1; idx < len(ints); idx++ {
+		if bytes.Compare(mbuckets.Int64Key(ints[idx-1]), mbuckets.Int64Key(ints[idx])) >= 0 {
+			t.Errorf("Key of %d does not sort before key of %d", ints[idx-1], ints[idx])
+		}
+
+		n, err := mbuckets.ParseInt64Key(mbuckets.Int64Key(ints[idx]))
+		if err != nil || n != ints[idx] {
+			t.Errorf("Decoded number %d does not match encoded number %d", n, ints[idx])
+		}
+	}
+
+	floats := []float64{math.Inf(-1), -1e10, -1.5, -0.5, 0, 0.5, 1.5, 1e10, math.Inf(1)}
+	keys := make([][]byte, len(floats))
+	for idx, f := range floats {
+		keys[idx] = mbuckets.Float64Key(f)
+
+		decoded, err := mbuckets.ParseFloat64Key(keys[idx])
+		if err != nil || decoded != f {
+			t.Errorf("Decoded number %g does not match encoded number %g", decoded, f)
+		}
+	}
+
+	if !sort.SliceIsSorted(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 }) {
+		t.Error("Keys of floating point numbers do not sort in numeric order")
+	}
+
+	_, err := mbuckets.ParseUint64Key([]byte("short"))
+	if err == nil {
+		t.Error("Decoding a key of invalid length did not fail")
+	}
+}
+
+func TestGetRangeUint64(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	for _, n := range []uint64{1, 9, 10, 99, 100, 1000} {
+		err = bucket.InsertUint64(n, []byte("value"))
+		if err != nil {
+			t.Errorf("Unable to insert numeric key in bucket. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Retrieving numeric keys in range: 9 to 100")
+	items, err := bucket.GetRangeUint64(9, 100)
+	if err != nil {
+		t.Errorf("Unable to retrieve numeric range from bucket. Error: %s", err.Error())
+	}
+
+	var found []uint64
+	for _, item := range items {
+		n, _ := mbuckets.ParseUint64Key(item.Key)
+		found = append(found, n)
+	}
+
+	if len(found) != 4 || found[0] != 9 || found[3] != 100 {
+		t.Errorf("Numeric keys %v in range do not match the keys inserted", found)
+	}
+}
@@ -0,0 +1,121 @@
+package mbuckets
+
+import (
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+// Name of the meta bucket holding the tokens of the operations applied by ApplyOpsOnce
+var appliedOpsBucketName = []byte("applied_ops")
+
+// Op is a write operation applied by ApplyOps
+type Op struct {
+	// Complete hierarchial name of the Bucket to apply the operation on, see DB.Bucket
+	Bucket []byte
+
+	// One of OpInsert, OpDelete, OpCreateBucket and OpDeleteBucket
+	Type string
+
+	// Key to insert or delete, unused for bucket operations
+	Key []byte
+
+	// Value to insert, unused for other operations
+	Value []byte
+}
+
+// batchOp returns the Batch operation performing this Op on the Buckets of DB `db`
+func (op Op) batchOp(db *DB) (batchOp, error) {
+	bucket := db.Bucket(op.Bucket)
+
+	switch op.Type {
+	case OpInsert:
+		return batchOp{batchPut, bucket, op.Key, op.Value, nil}, nil
+	case OpDelete:
+		return batchOp{batchDelete, bucket, op.Key, nil, nil}, nil
+	case OpCreateBucket:
+		return batchOp{batchCreateBucket, bucket, nil, nil, nil}, nil
+	case OpDeleteBucket:
+		return batchOp{batchDeleteBucket, bucket, nil, nil, nil}, nil
+	}
+
+	return batchOp{}, fmt.Errorf("Unknown operation type %q on bucket %s", op.Type, op.Bucket)
+}
+
+// ApplyOps applies the given operations in order in a single transaction, so that either all of them or none are applied
+func (db *DB) ApplyOps(ops []Op) error {
+	_, err := db.applyOps(nil, ops)
+	return err
+}
+
+// ApplyOpsOnce is ApplyOps recording the given token in the same transaction, so that replaying the operations is a no-op.
+// It reports false without applying the operations if operations with the same token have already been applied.
+//
+// Tokens are kept until removed with ForgetOps.
+func (db *DB) ApplyOpsOnce(token []byte, ops []Op) (applied bool, err error) {
+	if len(token) == 0 {
+		return false, fmt.Errorf("Empty token")
+	}
+
+	return db.applyOps(token, ops)
+}
+
+// ForgetOps removes the given token recorded by ApplyOpsOnce, so that operations with the same token can be applied again
+func (db *DB) ForgetOps(token []byte) error {
+	return db.update(nil, func(tx *bolt.Tx) error {
+		applied, err := metaBucket(tx, appliedOpsBucketName)
+		if err != nil {
+			return err
+		}
+
+		return applied.Delete(token)
+	})
+}
+
+func (db *DB) applyOps(token []byte, ops []Op) (bool, error) {
+	batchOps := make([]batchOp, len(ops))
+	for idx, op := range ops {
+		batchOp, err := op.batchOp(db)
+		if err != nil {
+			return false, err
+		}
+		batchOps[idx] = batchOp
+	}
+
+	applied := false
+	err := db.UpdateTx(func(tx *Tx) error {
+		applied = false
+
+		if token != nil {
+			tokens, err := metaBucket(tx.Tx, appliedOpsBucketName)
+			if err != nil {
+				return err
+			}
+
+			if tokens.Get(token) != nil {
+				return nil
+			}
+
+			err = tokens.Put(token, []byte{1})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, op := range batchOps {
+			err := op.apply(tx.Bind(op.bucket))
+			if err != nil {
+				return err
+			}
+		}
+
+		applied = true
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
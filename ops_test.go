@@ -0,0 +1,121 @@
+package mbuckets_test
+
+import (
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestApplyOps(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Applying operations")
+	err = db.ApplyOps([]mbuckets.Op{
+		{Bucket: []byte("Users/Admins"), Type: mbuckets.OpCreateBucket},
+		{Bucket: []byte("Users"), Type: mbuckets.OpInsert, Key: []byte("alice"), Value: []byte("1")},
+		{Bucket: []byte("Users"), Type: mbuckets.OpInsert, Key: []byte("bob"), Value: []byte("2")},
+		{Bucket: []byte("Users"), Type: mbuckets.OpDelete, Key: []byte("bob")},
+	})
+
+	if err != nil {
+		t.Errorf("Unable to apply operations. Error: %s", err.Error())
+	}
+
+	items, err := db.BucketString("Users").GetAllString()
+	if err != nil {
+		t.Errorf("Unable to get items. Error: %s", err.Error())
+	}
+
+	if len(items) != 1 || items["alice"] != "1" {
+		t.Errorf("Items %v do not match the applied operations", items)
+	}
+
+	exists, err := db.BucketString("Users/Admins").BucketExists()
+	if err != nil || !exists {
+		t.Errorf("Created bucket does not exist. Error: %v", err)
+	}
+
+	t.Log("Applying operations with an unknown type")
+	err = db.ApplyOps([]mbuckets.Op{
+		{Bucket: []byte("Users"), Type: mbuckets.OpInsert, Key: []byte("carol"), Value: []byte("3")},
+		{Bucket: []byte("Users"), Type: "rename"},
+	})
+
+	if err == nil {
+		t.Errorf("Operations with an unknown type were applied")
+	}
+
+	t.Log("Applying operations failing midway")
+	err = db.ApplyOps([]mbuckets.Op{
+		{Bucket: []byte("Users"), Type: mbuckets.OpInsert, Key: []byte("carol"), Value: []byte("3")},
+		{Bucket: []byte("Missing"), Type: mbuckets.OpDeleteBucket},
+	})
+
+	if err == nil {
+		t.Errorf("Deleting a missing bucket did not fail")
+	}
+
+	exists, err = db.BucketString("Users").ExistsString("carol")
+	if err != nil || exists {
+		t.Errorf("Operations of the failed transaction were applied. Error: %v", err)
+	}
+}
+
+func TestApplyOpsOnce(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	ops := []mbuckets.Op{
+		{Bucket: []byte("Counters"), Type: mbuckets.OpInsert, Key: []byte("hits"), Value: []byte("1")},
+	}
+
+	for i := 0; i < 2; i++ {
+		t.Logf("Applying operations with token op-1, attempt %d", i+1)
+		applied, err := db.ApplyOpsOnce([]byte("op-1"), ops)
+		if err != nil {
+			t.Errorf("Unable to apply operations. Error: %s", err.Error())
+		}
+
+		if applied != (i == 0) {
+			t.Errorf("Operations applied %t on attempt %d", applied, i+1)
+		}
+
+		err = db.BucketString("Counters").DeleteString("hits")
+		if err != nil {
+			t.Errorf("Unable to delete key. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Forgetting token op-1")
+	err = db.ForgetOps([]byte("op-1"))
+	if err != nil {
+		t.Errorf("Unable to forget token. Error: %s", err.Error())
+	}
+
+	applied, err := db.ApplyOpsOnce([]byte("op-1"), ops)
+	if err != nil || !applied {
+		t.Errorf("Operations were not applied after forgetting the token. Error: %v", err)
+	}
+
+	value, err := db.BucketString("Counters").GetString("hits")
+	if err != nil || value != "1" {
+		t.Errorf("Value %s does not match the applied operation. Error: %v", value, err)
+	}
+
+	t.Log("Applying operations with an empty token")
+	_, err = db.ApplyOpsOnce(nil, ops)
+	if err == nil {
+		t.Errorf("Operations with an empty token were applied")
+	}
+}
@@ -0,0 +1,108 @@
+package mbuckets
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/boltdb/bolt"
+)
+
+// PanicError is returned in place of a panic in a function called by mbuckets when panic recovery is enabled
+type PanicError struct {
+	// Complete hierarchial name of the Bucket the function was called for, nil for DB level functions
+	Bucket []byte
+
+	// The value passed to panic
+	Value interface{}
+
+	// The stack trace of the goroutine at the time of the panic
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Bucket == nil {
+		return fmt.Sprintf("Panic: %v\n%s", e.Value, e.Stack)
+	}
+
+	return fmt.Sprintf("Panic in bucket %s: %v\n%s", e.Bucket, e.Value, e.Stack)
+}
+
+// HookError is returned when a hook called by this DB fails after the operation it reports on has completed.
+// The operation itself succeeded, so a write returning a HookError has been committed and must not be retried.
+type HookError struct {
+	// The error of the hook, a *PanicError
+	Err error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("Hook failed: %s", e.Err)
+}
+
+// Unwrap returns the error of the hook, so that errors.As matches its *PanicError
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// isHookError reports whether the given error is the error of a hook, after the operation it reports on has succeeded
+func isHookError(err error) bool {
+	var hookErr *HookError
+	return errors.As(err, &hookErr)
+}
+
+// RecoverPanics sets whether panics in functions passed to Update, View, Map and their variants,
+// and in hooks called by this DB, are recovered and returned as a *PanicError.
+//
+// A panic inside a transaction rolls the transaction back.
+// A panic in a hook called after a commit, such as the hooks set by OnCommit, OnCommitInfo and OnTxStats or an EventSink,
+// is returned wrapped in a *HookError, but the transaction remains committed and is reported as such. A panic in a function called by ProcessConcurrently is returned
+// as its error, while a panic in a function called by Watch or WatchCompaction is dropped.
+func (db *DB) RecoverPanics(enabled bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.recoverPanics = enabled
+}
+
+// guard calls function `fn`, converting a panic into a *PanicError
+func guard(name []byte, fn func() error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{name, value, debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
+
+// callHook calls hook function `fn` for the bucket with the given name,
+// converting a panic into a *HookError wrapping a *PanicError if panic recovery is enabled
+func (db *DB) callHook(name []byte, fn func()) error {
+	db.mu.RLock()
+	recoverPanics := db.recoverPanics
+	db.mu.RUnlock()
+
+	if !recoverPanics {
+		fn()
+		return nil
+	}
+
+	err := guard(name, func() error {
+		fn()
+		return nil
+	})
+
+	if err != nil {
+		return &HookError{err}
+	}
+	return nil
+}
+
+// guardTx wraps transaction function `fn` to convert a panic into a *PanicError, rolling back the transaction
+func guardTx(name []byte, fn func(*bolt.Tx) error) func(*bolt.Tx) error {
+	return func(tx *bolt.Tx) error {
+		return guard(name, func() error {
+			return fn(tx)
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Enabling panic recovery")
+	db.RecoverPanics(true)
+
+	bucketName := []byte("Bucket1")
+	bucket := db.Bucket(bucketName)
+
+	t.Log("Running an update that panics")
+	err = bucket.Update(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		bucket.Put([]byte("key1"), []byte("value1"))
+		panic("simulated panic")
+	})
+
+	var panicErr *mbuckets.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Panic in update was not returned as PanicError. Error: %v", err)
+	}
+
+	t.Logf("Recovered panic in bucket: %s, Value: %v", panicErr.Bucket, panicErr.Value)
+	if !bytes.Equal(panicErr.Bucket, bucketName) || len(panicErr.Stack) == 0 {
+		t.Error("PanicError does not describe the panic")
+	}
+
+	t.Log("Checking that the update was rolled back")
+	err = bucket.InsertString("key2", "value2")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	_, err = bucket.GetString("key1")
+	if err == nil {
+		t.Error("Update that panicked was not rolled back")
+	}
+
+	t.Log("Mapping with a function that panics")
+	err = bucket.Map(func(k, v []byte) error {
+		panic("simulated panic")
+	})
+
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in map was not returned as PanicError. Error: %v", err)
+	}
+}
+
+func TestRecoverPanicsInHooks(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Enabling panic recovery")
+	db.RecoverPanics(true)
+
+	bucket := db.BucketString("Bucket1")
+	var panicErr *mbuckets.PanicError
+
+	t.Log("Inserting with an OnCommit hook that panics")
+	db.OnCommit(func([]mbuckets.Mutation) {
+		panic("simulated panic")
+	})
+
+	err = bucket.InsertString("key1", "value1")
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in OnCommit hook was not returned as PanicError. Error: %v", err)
+	}
+
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		return tx.BucketString("Bucket1").InsertString("key2", "value2")
+	})
+
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in OnCommit hook of a Tx was not returned as PanicError. Error: %v", err)
+	}
+
+	value, err := bucket.GetString("key2")
+	if err != nil || value != "value2" {
+		t.Errorf("Transaction with a panicking hook was not committed. Error: %v", err)
+	}
+
+	db.OnCommit(nil)
+
+	t.Log("Inserting with a Tx OnCommit hook that panics")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		tx.OnCommit(func([]mbuckets.Mutation) {
+			panic("simulated panic")
+		})
+		return tx.BucketString("Bucket1").InsertString("key3", "value3")
+	})
+
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in Tx.OnCommit hook was not returned as PanicError. Error: %v", err)
+	}
+
+	t.Log("Inserting with an EventSink that panics")
+	db.SetEventSink(panicSink{})
+
+	err = bucket.InsertString("key4", "value4")
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in EventSink was not returned as PanicError. Error: %v", err)
+	}
+
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		return tx.BucketString("Bucket1").InsertString("key5", "value5")
+	})
+
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in EventSink of a Tx was not returned as PanicError. Error: %v", err)
+	}
+
+	db.SetEventSink(nil)
+
+	t.Log("Inserting with an OnTxStats hook that panics")
+	db.TrackTxStats(true)
+	db.OnTxStats(func(mbuckets.TxStat) {
+		panic("simulated panic")
+	})
+
+	err = bucket.InsertString("key6", "value6")
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in OnTxStats hook was not returned as PanicError. Error: %v", err)
+	}
+
+	db.OnTxStats(nil)
+	db.TrackTxStats(false)
+
+	t.Log("Processing with a function that panics")
+	err = bucket.ProcessConcurrently(context.Background(), 2, func(item mbuckets.Item) error {
+		panic("simulated panic")
+	})
+
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Panic in ProcessConcurrently was not returned as PanicError. Error: %v", err)
+	}
+}
+
+func TestHookPanicAfterCommit(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	db.RecoverPanics(true)
+
+	commits, rollbacks := 0, 0
+	db.OnCommit(func([]mbuckets.Mutation) { commits++ })
+	db.OnRollback(func([]mbuckets.Mutation, error) { rollbacks++ })
+
+	sink := &testEventSink{}
+	db.SetEventSink(sink)
+
+	t.Log("Inserting with an OnCommitInfo hook that panics")
+	db.OnCommitInfo(func(mbuckets.CommitInfo) {
+		panic("simulated panic")
+	})
+
+	bucket := db.BucketString("Bucket1")
+	err = bucket.InsertString("key1", "value1")
+
+	var hookErr *mbuckets.HookError
+	if !errors.As(err, &hookErr) {
+		t.Errorf("Panic in OnCommitInfo hook was not returned as HookError. Error: %v", err)
+	}
+
+	value, err := bucket.GetString("key1")
+	if err != nil || value != "value1" {
+		t.Errorf("Insert with a panicking hook was not committed. Error: %v", err)
+	}
+
+	if commits != 1 || rollbacks != 0 {
+		t.Errorf("Committed insert was reported with %d commits and %d rollbacks", commits, rollbacks)
+	}
+
+	if len(sink.events) == 0 || sink.events[0].Err != nil {
+		t.Errorf("Event of the committed insert reports a failure: %+v", sink.events)
+	}
+
+	t.Log("Committing a Batch with an OnCommitInfo hook that panics")
+	batch := db.NewBatch()
+	batch.Put(bucket, []byte("key2"), []byte("value2"))
+
+	err = batch.Commit()
+	if !errors.As(err, &hookErr) {
+		t.Errorf("Panic in OnCommitInfo hook was not returned as HookError. Error: %v", err)
+	}
+
+	if batch.Len() != 0 {
+		t.Errorf("Committed batch kept %d queued operations", batch.Len())
+	}
+}
+
+func TestRecoverPanicsInWatch(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Enabling panic recovery")
+	db.RecoverPanics(true)
+
+	for _, name := range []string{"Bucket1", "Bucket2"} {
+		err = db.BucketString(name).InsertString("key1", "value1")
+		if err != nil {
+			t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+		}
+
+		err = db.BucketString(name).DeleteString("key1")
+		if err != nil {
+			t.Errorf("Unable to delete key. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Watching with callbacks that panic on the first call")
+	alerts := make(chan struct{}, 10)
+	calls := 0
+	stop, err := db.Watch(mbuckets.Thresholds{BucketCount: 1}, time.Millisecond, func(mbuckets.Alert) {
+		calls++
+		if calls == 1 {
+			panic("simulated panic")
+		}
+		alerts <- struct{}{}
+	})
+	if err != nil {
+		t.Fatalf("Unable to watch thresholds. Error: %s", err.Error())
+	}
+	defer stop()
+
+	advices := make(chan struct{}, 10)
+	adviceCalls := 0
+	stopCompaction, err := db.WatchCompaction(mbuckets.CompactionPolicy{DeletedKeys: 1}, time.Millisecond, func(mbuckets.CompactionAdvice) {
+		adviceCalls++
+		if adviceCalls == 1 {
+			panic("simulated panic")
+		}
+		advices <- struct{}{}
+	})
+	if err != nil {
+		t.Fatalf("Unable to watch compaction. Error: %s", err.Error())
+	}
+	defer stopCompaction()
+
+	for _, ch := range []chan struct{}{alerts, advices} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Error("Watching stopped after a callback panicked")
+		}
+	}
+}
+
+type panicSink struct{}
+
+func (panicSink) Emit(mbuckets.Event) {
+	panic("simulated panic")
+}
@@ -0,0 +1,68 @@
+package mbuckets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ErrSliceModified is returned, wrapped with the key, when a Map callback modifies a key or value slice in slice poisoning mode
+var ErrSliceModified = errors.New("Key/value slice modified by callback")
+
+// The byte written over the key/value slices handed to Map callbacks once their transaction has closed
+const poisonByte = 0xDB
+
+// SetSlicePoisoning sets whether Map, MapPrefix and MapRange hand their callbacks copies of the key/value slices,
+// and overwrite the copies with poison bytes once the transaction has closed.
+//
+// Slices retained by a callback past the transaction, which would silently change when bolt reuses their pages,
+// then consistently read as garbage, and a callback modifying a slice fails the iteration with ErrSliceModified.
+// All copies are held until the transaction closes, so slice poisoning is meant for tests only.
+func (db *DB) SetSlicePoisoning(enabled bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.slicePoisoning = enabled
+}
+
+// poisonSlices wraps iteration function `fn` on the bolt.Bucket with the given name for slice poisoning if enabled,
+// the returned release function must be called after the transaction has closed
+func (db *DB) poisonSlices(name []byte, fn func([]byte, []byte) error) (func([]byte, []byte) error, func()) {
+	db.mu.RLock()
+	enabled := db.slicePoisoning
+	db.mu.RUnlock()
+
+	if !enabled {
+		return fn, func() {}
+	}
+
+	var handed [][]byte
+
+	wrapped := func(k, v []byte) error {
+		key := append([]byte(nil), k...)
+		var value []byte
+		if v != nil {
+			value = append([]byte{}, v...)
+		}
+
+		handed = append(handed, key, value)
+
+		err := fn(key, value)
+
+		if !bytes.Equal(key, k) || !bytes.Equal(value, v) {
+			return fmt.Errorf("%w: key %s in bucket %s", ErrSliceModified, k, name)
+		}
+
+		return err
+	}
+
+	release := func() {
+		for _, slice := range handed {
+			for idx := range slice {
+				slice[idx] = poisonByte
+			}
+		}
+	}
+
+	return wrapped, release
+}
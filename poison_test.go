@@ -0,0 +1,75 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestSlicePoisoning(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting key/value pairs in bucket")
+	err = bucket.InsertAllString(map[string]string{"key1": "value1", "key2": "value2"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Enabling slice poisoning")
+	db.SetSlicePoisoning(true)
+
+	var retained [][]byte
+
+	t.Log("Retaining values past the transaction")
+	err = bucket.Map(func(k, v []byte) error {
+		retained = append(retained, v)
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Unable to map over bucket. Error: %s", err.Error())
+	}
+
+	if len(retained) != 2 {
+		t.Fatalf("Number of retained values %d does not match the key/value pairs inserted", len(retained))
+	}
+
+	for _, v := range retained {
+		t.Logf("Retained value: %x", v)
+		if !bytes.Equal(v, bytes.Repeat([]byte{0xDB}, len(v))) {
+			t.Errorf("Retained value %x was not poisoned", v)
+		}
+	}
+
+	t.Log("Modifying a value in the callback")
+	err = bucket.MapPrefix([]byte("key"), func(k, v []byte) error {
+		v[0] = 'x'
+		return nil
+	})
+
+	if !errors.Is(err, mbuckets.ErrSliceModified) {
+		t.Errorf("Modifying a value did not return ErrSliceModified. Error: %v", err)
+	}
+
+	t.Log("Disabling slice poisoning")
+	db.SetSlicePoisoning(false)
+
+	value, err := bucket.GetString("key1")
+	if err != nil {
+		t.Errorf("Unable to retrieve value. Error: %s", err.Error())
+	}
+
+	if value != "value1" {
+		t.Errorf("Stored value %s was changed by slice poisoning", value)
+	}
+}
@@ -0,0 +1,93 @@
+package mbuckets
+
+import (
+	"bytes"
+
+	"github.com/boltdb/bolt"
+)
+
+// RangeOptions specifies the keys visited by MapRangeWith and GetRangeWith
+type RangeOptions struct {
+	// Lower bound of the range, nil for no lower bound
+	Min []byte
+
+	// Upper bound of the range, nil for no upper bound
+	Max []byte
+
+	// Whether a key equal to Min or Max is left out of the range, by default both bounds are inclusive
+	ExcludeMin bool
+	ExcludeMax bool
+
+	// If set, only keys with this prefix are in the range
+	Prefix []byte
+}
+
+// start returns the key to seek to for the first key in the range
+func (opts RangeOptions) start() []byte {
+	if bytes.Compare(opts.Prefix, opts.Min) > 0 {
+		return opts.Prefix
+	}
+
+	return opts.Min
+}
+
+// afterMin reports whether the given key is within the lower bound of the range
+func (opts RangeOptions) afterMin(k []byte) bool {
+	cmp := bytes.Compare(k, opts.Min)
+	return cmp > 0 || cmp == 0 && !opts.ExcludeMin
+}
+
+// beforeEnd reports whether the given key is within the upper bound and prefix of the range
+func (opts RangeOptions) beforeEnd(k []byte) bool {
+	if !bytes.HasPrefix(k, opts.Prefix) {
+		return false
+	}
+
+	if opts.Max == nil {
+		return true
+	}
+
+	cmp := bytes.Compare(k, opts.Max)
+	return cmp < 0 || cmp == 0 && !opts.ExcludeMax
+}
+
+// MapRangeWith performs a view operation specified by function `fn` on all key value pairs in this Bucket
+// within the range specified by the given RangeOptions, see MapRange
+func (b *Bucket) MapRangeWith(opts RangeOptions, fn func([]byte, []byte) error) error {
+	fn, finish := b.scanErrors(fn)
+	fn, release := b.DB.poisonSlices(b.Name, b.DB.iterateFailpoint(b.Name, fn))
+	defer release()
+
+	return finish(b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		cursor := bucket.Cursor()
+
+		for k, v := cursor.Seek(opts.start()); k != nil && opts.beforeEnd(k); k, v = cursor.Next() {
+			if !opts.afterMin(k) {
+				continue
+			}
+
+			err := fn(k, v)
+			if err != nil {
+				return stopped(err)
+			}
+		}
+
+		return nil
+	}))
+}
+
+// GetRangeWith retrieves all the key/value pairs from the bolt.Bucket specified by this Bucket
+// within the range specified by the given RangeOptions, see GetRange
+func (b *Bucket) GetRangeWith(opts RangeOptions) ([]Item, error) {
+	var items []Item
+	err := b.MapRangeWith(opts, func(k, v []byte) error {
+		if v != nil {
+			items = append(items, Item{copyKey(k), copyKey(v)})
+		}
+
+		return nil
+	})
+
+	b.sortItems(items)
+	return items, err
+}
@@ -0,0 +1,58 @@
+package mbuckets_test
+
+import (
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestGetRangeWith(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting items")
+	err = bucket.InsertAllString(map[string]string{"a1": "1", "a2": "2", "a3": "3", "b1": "4", "b2": "5"})
+	if err != nil {
+		t.Errorf("Unable to insert items in bucket. Error: %s", err.Error())
+	}
+
+	tests := []struct {
+		opts mbuckets.RangeOptions
+		keys string
+	}{
+		{mbuckets.RangeOptions{Min: []byte("a2"), Max: []byte("b1")}, "a2a3b1"},
+		{mbuckets.RangeOptions{Min: []byte("a2"), Max: []byte("b1"), ExcludeMin: true}, "a3b1"},
+		{mbuckets.RangeOptions{Min: []byte("a2"), Max: []byte("b1"), ExcludeMax: true}, "a2a3"},
+		{mbuckets.RangeOptions{Max: []byte("a3"), ExcludeMax: true}, "a1a2"},
+		{mbuckets.RangeOptions{Min: []byte("a3")}, "a3b1b2"},
+		{mbuckets.RangeOptions{Prefix: []byte("b")}, "b1b2"},
+		{mbuckets.RangeOptions{Min: []byte("a2"), ExcludeMin: true, Prefix: []byte("a")}, "a3"},
+		{mbuckets.RangeOptions{Max: []byte("b1"), Prefix: []byte("b")}, "b1"},
+		{mbuckets.RangeOptions{Min: []byte("b"), Prefix: []byte("a")}, ""},
+		{mbuckets.RangeOptions{Min: []byte("a1"), Max: []byte("a1"), ExcludeMax: true}, ""},
+	}
+
+	for _, test := range tests {
+		t.Logf("Retrieving items in range %+v", test.opts)
+		results, err := bucket.GetRangeWith(test.opts)
+		if err != nil {
+			t.Errorf("Unable to retrieve items for given range from bucket. Error: %s", err.Error())
+		}
+
+		keys := ""
+		for _, item := range results {
+			keys += string(item.Key)
+		}
+
+		if keys != test.keys {
+			t.Errorf("Retrieved keys %s do not match the expected keys %s", keys, test.keys)
+		}
+	}
+}
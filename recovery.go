@@ -0,0 +1,166 @@
+package mbuckets
+
+import (
+	"bytes"
+
+	"github.com/boltdb/bolt"
+)
+
+// RecoveryReport lists the state of mbuckets removed by Recover
+type RecoveryReport struct {
+	// Tokens of checkpoints that could not be decoded
+	Checkpoints []ResumeToken
+
+	// Names of missing bolt.Buckets that were still marked write-once, see SetWriteOnce, using "/" as the separator
+	WriteOnce [][]byte
+}
+
+// Repaired reports whether Recover removed anything
+func (r RecoveryReport) Repaired() bool {
+	return len(r.Checkpoints) > 0 || len(r.WriteOnce) > 0
+}
+
+// Recover removes the state kept by mbuckets that is left over from crashed processes or from bolt.Buckets
+// deleted directly through the embedded bolt.DB, and reports what was removed. Open and OpenWith call it,
+// unless the bolt.DB is opened read-only, see Recovery.
+//
+// Checkpoints that can not be decoded are removed, while valid checkpoints are kept so that their operations can be resumed.
+// Write-once marks of bolt.Buckets that no longer exist are removed, so that bolt.Buckets created at the same paths later
+// do not inherit them. Fences are kept, as they may be set before their bolt.Buckets are created, see SetFence.
+//
+// The state is inspected in a read-only transaction first, so a write transaction is only made if there is something to remove.
+func (db *DB) Recover() (RecoveryReport, error) {
+	var report RecoveryReport
+
+	err := db.view(nil, func(tx *bolt.Tx) error {
+		report, _ = scanRecovery(tx)
+		return nil
+	})
+
+	if err != nil || !report.Repaired() {
+		return RecoveryReport{}, err
+	}
+
+	err = db.update(nil, func(tx *bolt.Tx) error {
+		var writeOnceKeys [][]byte
+		report, writeOnceKeys = scanRecovery(tx)
+
+		if !report.Repaired() {
+			return nil
+		}
+
+		meta := tx.Bucket(metaBucketName)
+
+		err := deleteKeys(meta.Bucket(checkpointsBucketName), tokenKeys(report.Checkpoints))
+		if err != nil {
+			return err
+		}
+
+		return deleteKeys(meta.Bucket(writeOnceBucketName), writeOnceKeys)
+	})
+
+	if err != nil {
+		return RecoveryReport{}, err
+	}
+
+	return report, nil
+}
+
+// Recovery returns what was removed by the Recover call of Open or OpenWith
+func (db *DB) Recovery() RecoveryReport {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.recovery
+}
+
+// scanRecovery returns what Recover has to remove in transaction `tx`, and the keys of the write-once marks to remove
+func scanRecovery(tx *bolt.Tx) (report RecoveryReport, writeOnceKeys [][]byte) {
+	meta := tx.Bucket(metaBucketName)
+	if meta == nil {
+		return report, nil
+	}
+
+	report.Checkpoints = invalidCheckpoints(meta.Bucket(checkpointsBucketName))
+	writeOnceKeys, report.WriteOnce = missingPaths(tx, meta.Bucket(writeOnceBucketName))
+
+	return report, writeOnceKeys
+}
+
+// invalidCheckpoints returns the tokens of the checkpoints that can not be decoded in the given meta bucket
+func invalidCheckpoints(checkpoints *bolt.Bucket) []ResumeToken {
+	if checkpoints == nil {
+		return nil
+	}
+
+	var invalid []ResumeToken
+	checkpoints.ForEach(func(k, v []byte) error {
+		cp := &checkpoint{}
+		if v == nil || cp.decode(ResumeToken(k), v) != nil {
+			invalid = append(invalid, ResumeToken(k))
+		}
+		return nil
+	})
+
+	return invalid
+}
+
+// missingPaths returns the keys of the entries keyed by the pathKey of missing bolt.Buckets in the given meta bucket,
+// and the names of the missing bolt.Buckets
+func missingPaths(tx *bolt.Tx, entries *bolt.Bucket) (keys, names [][]byte) {
+	if entries == nil {
+		return nil, nil
+	}
+
+	entries.ForEach(func(k, v []byte) error {
+		buckets, ok := splitPathKey(k)
+		if ok && bucketExists(tx, buckets) {
+			return nil
+		}
+
+		keys = append(keys, copyKey(k))
+		names = append(names, bytes.Join(buckets, []byte("/")))
+		return nil
+	})
+
+	return keys, names
+}
+
+// tokenKeys returns the keys under which the given checkpoints are stored
+func tokenKeys(tokens []ResumeToken) [][]byte {
+	keys := make([][]byte, len(tokens))
+	for idx, token := range tokens {
+		keys[idx] = []byte(token)
+	}
+
+	return keys
+}
+
+// deleteKeys deletes the given keys from the given bolt.Bucket
+func deleteKeys(bucket *bolt.Bucket, keys [][]byte) error {
+	for _, key := range keys {
+		err := bucket.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bucketExists reports whether the bolt.Bucket with the given path exists
+func bucketExists(tx *bolt.Tx, buckets [][]byte) bool {
+	if len(buckets) == 0 {
+		return false
+	}
+
+	bucket := tx.Bucket(buckets[0])
+	for _, name := range buckets[1:] {
+		if bucket == nil {
+			return false
+		}
+		bucket = bucket.Bucket(name)
+	}
+
+	return bucket != nil
+}
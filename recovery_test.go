@@ -0,0 +1,212 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestRecover(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	report, err := db.Recover()
+	if err != nil {
+		t.Errorf("Unable to recover empty db. Error: %s", err.Error())
+	}
+
+	if report.Repaired() {
+		t.Errorf("Recovery of an empty db repaired something: %+v", report)
+	}
+
+	for _, name := range []string{"Kept/Child", "Dropped/Child"} {
+		t.Logf("Setting fence and write-once on bucket: %s", name)
+		err = db.BucketString(name).SetFence(5)
+		if err != nil {
+			t.Errorf("Unable to set fence. Error: %s", err.Error())
+		}
+
+		err = db.BucketString(name).SetWriteOnce()
+		if err != nil {
+			t.Errorf("Unable to set write-once. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Deleting bucket Dropped through bolt and writing an invalid checkpoint")
+	err = db.DB.DB.Update(func(tx *bolt.Tx) error {
+		err := tx.DeleteBucket([]byte("Dropped"))
+		if err != nil {
+			return err
+		}
+
+		checkpoints, err := tx.Bucket([]byte("__mbuckets__")).CreateBucketIfNotExists([]byte("checkpoints"))
+		if err != nil {
+			return err
+		}
+		return checkpoints.Put([]byte("import-99"), []byte{0xff})
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update through bolt. Error: %s", err.Error())
+	}
+
+	t.Log("Recovering db")
+	report, err = db.Recover()
+	if err != nil {
+		t.Errorf("Unable to recover db. Error: %s", err.Error())
+	}
+
+	t.Logf("Recovery report: %+v", report)
+	if len(report.Checkpoints) != 1 || report.Checkpoints[0] != "import-99" {
+		t.Errorf("Recovered checkpoints %v do not match the invalid checkpoint", report.Checkpoints)
+	}
+
+	if len(report.WriteOnce) != 1 || string(report.WriteOnce[0]) != "Dropped/Child" {
+		t.Errorf("Recovered write-once marks %q do not match the deleted bucket", report.WriteOnce)
+	}
+
+	fence, err := db.BucketString("Kept/Child").Fence()
+	if err != nil || fence != 5 {
+		t.Errorf("Fence %d of the existing bucket was not kept. Error: %v", fence, err)
+	}
+
+	writeOnce, err := db.BucketString("Dropped/Child").IsWriteOnce()
+	if err != nil || writeOnce {
+		t.Errorf("Recreated bucket inherited the write-once mark. Error: %v", err)
+	}
+}
+
+func TestRecoverKeepsFences(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Setting fence 5 on bucket jobs before creating it")
+	err = db.BucketString("jobs").SetFence(5)
+	if err != nil {
+		t.Errorf("Unable to set fence. Error: %s", err.Error())
+	}
+
+	_, err = db.Recover()
+	if err != nil {
+		t.Errorf("Unable to recover db. Error: %s", err.Error())
+	}
+
+	t.Log("Writing with stale token 3")
+	err = db.BucketString("jobs").WithFence(3).InsertString("key1", "value1")
+	if !errors.Is(err, mbuckets.ErrStaleFence) {
+		t.Errorf("Write with a stale token after recovery did not fail with ErrStaleFence. Error: %v", err)
+	}
+
+	t.Log("Deleting bucket jobs")
+	err = db.BucketString("jobs").WithFence(5).InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	err = db.BucketString("jobs").DeleteBucket()
+	if err != nil {
+		t.Errorf("Unable to delete bucket. Error: %s", err.Error())
+	}
+
+	fence, err := db.BucketString("jobs").Fence()
+	if err != nil || fence != 0 {
+		t.Errorf("Fence %d was not removed with its bucket. Error: %v", fence, err)
+	}
+}
+
+func TestOpenRecovers(t *testing.T) {
+	path := tempFile()
+	defer os.Remove(path)
+
+	t.Log("Writing an invalid checkpoint through bolt")
+	boltDB, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("Unable to open bolt db. Error: %s", err.Error())
+	}
+
+	err = boltDB.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists([]byte("__mbuckets__"))
+		if err != nil {
+			return err
+		}
+
+		checkpoints, err := meta.CreateBucketIfNotExists([]byte("checkpoints"))
+		if err != nil {
+			return err
+		}
+		return checkpoints.Put([]byte("import-1"), []byte{0xff})
+	})
+	boltDB.Close()
+
+	if err != nil {
+		t.Errorf("Unable to update through bolt. Error: %s", err.Error())
+	}
+
+	t.Log("Opening the db")
+	db, err := mbuckets.Open(path)
+	if err != nil {
+		t.Fatalf("Unable to open db. Error: %s", err.Error())
+	}
+	defer db.Close()
+
+	report := db.Recovery()
+	t.Logf("Recovery report: %+v", report)
+	if len(report.Checkpoints) != 1 || report.Checkpoints[0] != "import-1" {
+		t.Errorf("Open did not remove the invalid checkpoint")
+	}
+}
+
+func TestOpenWithoutRecovery(t *testing.T) {
+	path := tempFile()
+	defer os.Remove(path)
+
+	txID := func(db *mbuckets.DB) (id int) {
+		db.View(func(tx *bolt.Tx) error {
+			id = tx.ID()
+			return nil
+		})
+		return id
+	}
+
+	t.Log("Opening a new db")
+	db, err := mbuckets.Open(path)
+	if err != nil {
+		t.Fatalf("Unable to open db. Error: %s", err.Error())
+	}
+
+	err = db.BucketString("Bucket1").InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	before := txID(db)
+	db.Close()
+
+	t.Log("Reopening the db with nothing to recover")
+	db, err = mbuckets.Open(path)
+	if err != nil {
+		t.Fatalf("Unable to open db. Error: %s", err.Error())
+	}
+	defer db.Close()
+
+	if after := txID(db); after != before {
+		t.Errorf("Open made a write transaction with nothing to recover: transaction %d after %d", after, before)
+	}
+
+	if db.Recovery().Repaired() {
+		t.Errorf("Recovery report %+v lists state to remove", db.Recovery())
+	}
+}
@@ -0,0 +1,54 @@
+package mbuckets
+
+import (
+	"log"
+	"runtime"
+)
+
+// Acquire takes an additional reference to this DB for a component sharing it.
+// Every call to Acquire must be matched by a call to Release.
+func (db *DB) Acquire() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return ErrDBClosed
+	}
+
+	db.refs++
+	return nil
+}
+
+// Release drops a reference to this DB, and closes it when the reference taken by Open is dropped
+// after all references taken with Acquire.
+func (db *DB) Release() error {
+	db.mu.Lock()
+	if db.refs > 0 {
+		db.refs--
+		db.mu.Unlock()
+		return nil
+	}
+	db.mu.Unlock()
+
+	return db.Close()
+}
+
+// DetectLeaks reports this DB to function `fn` if it becomes unreachable without being closed,
+// and then closes the embedded bolt.DB to release its file lock.
+// If `fn` is nil, the leak is logged using the standard logger.
+func (db *DB) DetectLeaks(fn func(path string)) {
+	if fn == nil {
+		fn = func(path string) {
+			log.Printf("mbuckets: DB %s was not closed before becoming unreachable", path)
+		}
+	}
+
+	runtime.SetFinalizer(db, func(db *DB) {
+		if db.IsClosed() {
+			return
+		}
+
+		fn(db.Path())
+		db.Close()
+	})
+}
@@ -0,0 +1,93 @@
+package mbuckets_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Acquiring two references")
+	for i := 0; i < 2; i++ {
+		err = db.Acquire()
+		if err != nil {
+			t.Errorf("Unable to acquire reference. Error: %s", err.Error())
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		t.Log("Releasing an acquired reference")
+		err = db.Release()
+		if err != nil {
+			t.Errorf("Unable to release reference. Error: %s", err.Error())
+		}
+
+		if db.IsClosed() {
+			t.Fatal("Test db was closed while references were still held")
+		}
+	}
+
+	t.Log("Releasing the last reference")
+	err = db.Release()
+	if err != nil {
+		t.Errorf("Unable to release reference. Error: %s", err.Error())
+	}
+
+	if !db.IsClosed() {
+		t.Error("Test db was not closed when the last reference was released")
+	}
+
+	err = db.Acquire()
+	if err == nil {
+		t.Error("Reference acquired on closed db")
+	}
+}
+
+func TestDetectLeaks(t *testing.T) {
+	fileName := tempFile()
+	leaks := make(chan string, 1)
+
+	func() {
+		t.Log("Opening a db and leaking it")
+		db, err := mbuckets.Open(fileName)
+		if err != nil {
+			t.Fatalf("Unable to open the db. Error: %s", err.Error())
+		}
+
+		db.DetectLeaks(func(path string) {
+			leaks <- path
+		})
+	}()
+
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+
+		select {
+		case path := <-leaks:
+			t.Logf("Leak reported for db: %s", path)
+			if path != fileName {
+				t.Error("Leak reported for a different db")
+			}
+
+			db, err := mbuckets.Open(fileName)
+			if err != nil {
+				t.Fatalf("Unable to reopen the leaked db. Error: %s", err.Error())
+			}
+			(&TestDB{db}).Close()
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	t.Error("Leaked db was not reported")
+}
@@ -0,0 +1,80 @@
+package mbuckets
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// RetryPolicy configures OpenWithRetry and UpdateWithRetry
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one, less than 1 for a single attempt
+	Attempts int
+
+	// Delay before the first retry, doubled before every further retry
+	Backoff time.Duration
+
+	// Maximum delay between two attempts, zero for no maximum
+	MaxBackoff time.Duration
+
+	// Retryable reports whether a failed attempt is retried. If nil, IsTransient is used.
+	Retryable func(error) bool
+}
+
+// IsTransient reports whether the error is a transient failure that may not recur on retry,
+// such as a timeout waiting for the database file lock or a failed remap of the database file
+func IsTransient(err error) bool {
+	return errors.Is(err, bolt.ErrTimeout) || errors.Is(err, ErrRemap)
+}
+
+// OpenWithRetry is OpenWith retrying on transient failures according to the given policy, such as bolt.ErrTimeout
+// returned while another process holds the lock on the database file for longer than options.Timeout.
+// The error of the last attempt is returned.
+func OpenWithRetry(path string, mode os.FileMode, options *bolt.Options, policy RetryPolicy) (*DB, error) {
+	var db *DB
+
+	err := policy.run(func() (err error) {
+		db, err = OpenWith(path, mode, options)
+		return err
+	})
+
+	return db, err
+}
+
+// UpdateWithRetry is Update retrying function `fn` on transient failures according to the given policy.
+// The error of the last attempt is returned. Function `fn` must be idempotent.
+// A *HookError is never retried, as the transaction has been committed.
+//
+// Update itself retries a failed remap once, so with IsTransient the further attempts only help with repeated remap failures.
+// Timeouts waiting for the lock held by another process happen when opening the database file, see OpenWithRetry.
+func (db *DB) UpdateWithRetry(fn func(*bolt.Tx) error, policy RetryPolicy) error {
+	return policy.run(func() error {
+		return db.Update(fn)
+	})
+}
+
+// run calls function `fn` until it succeeds, fails with an error that is not retryable, or the attempts are exhausted
+func (policy RetryPolicy) run(fn func() error) error {
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsTransient
+	}
+
+	backoff := policy.Backoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || err == ErrDBClosed || isHookError(err) || attempt >= policy.Attempts || !retryable(err) {
+			return err
+		}
+
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
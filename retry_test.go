@@ -0,0 +1,92 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestUpdateWithRetry(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	policy := mbuckets.RetryPolicy{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	attempts := 0
+	errPermanent := errors.New("permanent")
+
+	t.Log("Updating with a function failing permanently")
+	err = db.UpdateWithRetry(func(tx *bolt.Tx) error {
+		attempts++
+		return errPermanent
+	}, policy)
+
+	if err != errPermanent || attempts != 1 {
+		t.Errorf("Update retried a permanent failure: %d attempts. Error: %v", attempts, err)
+	}
+
+	attempts = 0
+	t.Log("Updating with a custom retryable function")
+	policy.Retryable = func(err error) bool {
+		return err == errPermanent
+	}
+
+	err = db.UpdateWithRetry(func(tx *bolt.Tx) error {
+		attempts++
+		return errPermanent
+	}, policy)
+
+	if err != errPermanent || attempts != 3 {
+		t.Errorf("Update did not retry with the custom retryable function: %d attempts. Error: %v", attempts, err)
+	}
+}
+
+func TestOpenWithRetry(t *testing.T) {
+	path := tempFile()
+	defer os.Remove(path)
+
+	t.Log("Holding the lock on the database file from another handle")
+	holder, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("Unable to open bolt db. Error: %s", err.Error())
+	}
+
+	options := &bolt.Options{Timeout: 20 * time.Millisecond}
+
+	t.Log("Opening without retry while the lock is held")
+	_, err = mbuckets.OpenWithRetry(path, 0600, options, mbuckets.RetryPolicy{})
+	if err != bolt.ErrTimeout {
+		t.Errorf("Open did not time out while the lock was held. Error: %v", err)
+	}
+
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		holder.Close()
+		close(released)
+	}()
+
+	t.Log("Opening with retry until the lock is released")
+	policy := mbuckets.RetryPolicy{Attempts: 20, Backoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+	db, err := mbuckets.OpenWithRetry(path, 0600, options, policy)
+	<-released
+
+	if err != nil {
+		t.Fatalf("Open with retry failed. Error: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = db.BucketString("Bucket1").InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+}
@@ -0,0 +1,106 @@
+package mbuckets
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStop can be returned by the function passed to DB.Map, Bucket.Map, MapPrefix, MapRange and MapRangeWith
+// to end the iteration early, which then returns nil. It ends the iteration with either ScanErrorPolicy.
+var ErrStop = errors.New("Stop iteration")
+
+// ScanErrorPolicy decides how Map, MapPrefix and MapRange handle an error returned by their function `fn`
+type ScanErrorPolicy int
+
+const (
+	// ScanStop stops the iteration at the first error, which is returned. This is the default.
+	ScanStop ScanErrorPolicy = iota + 1
+
+	// ScanContinue visits all key/value pairs, unless function `fn` returns ErrStop, and returns a *ScanError collecting the errors of all failed keys
+	ScanContinue
+)
+
+// ScanError is returned by Map, MapPrefix and MapRange with ScanContinue when function `fn` failed for some keys
+type ScanError struct {
+	// Complete hierarchial name of the scanned Bucket
+	Bucket []byte
+
+	// The keys function `fn` failed for, in key order
+	Keys [][]byte
+
+	// The errors returned by function `fn`, one per key
+	Errors []error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("Scan of bucket %s failed for %d keys, first key %s: %s", e.Bucket, len(e.Keys), e.Keys[0], e.Errors[0])
+}
+
+// Unwrap returns the errors returned by function `fn`, so that errors.Is and errors.As match any of them
+func (e *ScanError) Unwrap() []error {
+	return e.Errors
+}
+
+// SetScanErrorPolicy sets the ScanErrorPolicy of the Buckets of this DB that do not set their own, see Bucket.WithScanErrorPolicy
+func (db *DB) SetScanErrorPolicy(policy ScanErrorPolicy) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.scanPolicy = policy
+}
+
+// WithScanErrorPolicy sets the ScanErrorPolicy of this Bucket, overriding the default of the DB,
+// and returns a pointer to this Bucket. Passing zero reverts to the default of the DB.
+func (b *Bucket) WithScanErrorPolicy(policy ScanErrorPolicy) *Bucket {
+	b.scanPolicy = policy
+	return b
+}
+
+// scanErrors wraps iteration function `fn` to collect its errors if the ScanErrorPolicy of this Bucket is ScanContinue.
+// The returned finish function converts the error of the iteration into the error to return.
+func (b *Bucket) scanErrors(fn func([]byte, []byte) error) (func([]byte, []byte) error, func(error) error) {
+	policy := b.scanPolicy
+	if policy == 0 {
+		b.DB.mu.RLock()
+		policy = b.DB.scanPolicy
+		b.DB.mu.RUnlock()
+	}
+
+	if policy != ScanContinue {
+		return fn, func(err error) error { return err }
+	}
+
+	scanErr := &ScanError{Bucket: b.Name}
+
+	wrapped := func(k, v []byte) error {
+		err := fn(k, v)
+		if err == nil || err == ErrStop {
+			return err
+		}
+
+		key := make([]byte, len(k))
+		copy(key, k)
+
+		scanErr.Keys = append(scanErr.Keys, key)
+		scanErr.Errors = append(scanErr.Errors, err)
+		return nil
+	}
+
+	finish := func(err error) error {
+		if err != nil || len(scanErr.Keys) == 0 {
+			return err
+		}
+		return scanErr
+	}
+
+	return wrapped, finish
+}
+
+// stopped converts the error of an iteration ended early by ErrStop into nil
+func stopped(err error) error {
+	if err == ErrStop {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,174 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestScanErrorPolicy(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting key/value pairs in bucket")
+	err = bucket.InsertAllString(map[string]string{"key1": "good", "key2": "bad", "key3": "good", "key4": "bad"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	errBad := errors.New("bad value")
+
+	var visited []string
+	clean := func(k, v []byte) error {
+		visited = append(visited, string(k))
+		if strings.HasPrefix(string(v), "bad") {
+			return errBad
+		}
+		return nil
+	}
+
+	t.Log("Scanning with the default policy")
+	err = bucket.Map(clean)
+	if err != errBad {
+		t.Errorf("Scan did not return the first error. Error: %v", err)
+	}
+
+	if len(visited) != 2 {
+		t.Errorf("Scan visited %d keys instead of stopping at the first error", len(visited))
+	}
+
+	t.Log("Setting the scan error policy of the db to continue")
+	db.SetScanErrorPolicy(mbuckets.ScanContinue)
+
+	visited = nil
+	err = bucket.MapPrefix([]byte("key"), clean)
+
+	var scanErr *mbuckets.ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("Scan did not return a ScanError. Error: %v", err)
+	}
+
+	t.Logf("Scan error: %s", err.Error())
+	if len(visited) != 4 {
+		t.Errorf("Scan visited %d keys instead of all of them", len(visited))
+	}
+
+	if len(scanErr.Keys) != 2 || string(scanErr.Keys[0]) != "key2" || string(scanErr.Keys[1]) != "key4" {
+		t.Errorf("Scan error keys %q do not match the failed keys", scanErr.Keys)
+	}
+
+	if !errors.Is(err, errBad) {
+		t.Error("Scan error does not match the errors of the function")
+	}
+
+	t.Log("Overriding the scan error policy of the bucket to stop")
+	visited = nil
+	err = db.BucketString("Bucket1").WithScanErrorPolicy(mbuckets.ScanStop).MapRange([]byte("key1"), []byte("key4"), clean)
+	if err != errBad || len(visited) != 2 {
+		t.Errorf("Scan with the bucket policy did not stop at the first error. Error: %v", err)
+	}
+
+	t.Log("Scanning without errors with the continue policy")
+	err = bucket.Map(func(k, v []byte) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Scan without errors failed. Error: %s", err.Error())
+	}
+}
+
+func TestErrStop(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Inserting items")
+	err = db.BucketString("Bucket1").InsertAllString(map[string]string{"key1": "1", "key2": "2", "key3": "3", "key4": "4"})
+	if err != nil {
+		t.Errorf("Unable to insert items in bucket. Error: %s", err.Error())
+	}
+
+	err = db.BucketString("Bucket2").InsertString("key1", "1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair in bucket. Error: %s", err.Error())
+	}
+
+	var visited []string
+	stopAtKey2 := func(k, v []byte) error {
+		visited = append(visited, string(k))
+		if string(k) == "key2" {
+			return mbuckets.ErrStop
+		}
+		return nil
+	}
+
+	for _, policy := range []mbuckets.ScanErrorPolicy{mbuckets.ScanStop, mbuckets.ScanContinue} {
+		bucket := db.BucketString("Bucket1").WithScanErrorPolicy(policy)
+
+		scans := map[string]func() error{
+			"Map":       func() error { return bucket.Map(stopAtKey2) },
+			"MapPrefix": func() error { return bucket.MapPrefix([]byte("key"), stopAtKey2) },
+			"MapRange":  func() error { return bucket.MapRange([]byte("key1"), []byte("key4"), stopAtKey2) },
+			"MapRangeWith": func() error {
+				return bucket.MapRangeWith(mbuckets.RangeOptions{Prefix: []byte("key")}, stopAtKey2)
+			},
+		}
+
+		for name, scan := range scans {
+			t.Logf("Stopping %s with policy %d", name, policy)
+			visited = nil
+
+			err = scan()
+			if err != nil {
+				t.Errorf("%s stopped with ErrStop returned an error. Error: %s", name, err.Error())
+			}
+
+			if len(visited) != 2 {
+				t.Errorf("%s visited keys %v after ErrStop", name, visited)
+			}
+		}
+	}
+
+	t.Log("Stopping a scan with ErrStop after a failed key with the continue policy")
+	errBad := errors.New("Bad value")
+	err = db.BucketString("Bucket1").WithScanErrorPolicy(mbuckets.ScanContinue).Map(func(k, v []byte) error {
+		switch string(k) {
+		case "key1":
+			return errBad
+		case "key2":
+			return mbuckets.ErrStop
+		}
+		t.Errorf("Scan visited key %s after ErrStop", k)
+		return nil
+	})
+
+	if !errors.Is(err, errBad) {
+		t.Errorf("Stopped scan did not report the failed key. Error: %v", err)
+	}
+
+	t.Log("Stopping DB.Map")
+	count := 0
+	err = db.Map(func(name []byte, bucket *bolt.Bucket) error {
+		count++
+		return mbuckets.ErrStop
+	})
+
+	if err != nil || count != 1 {
+		t.Errorf("DB.Map visited %d buckets after ErrStop. Error: %v", count, err)
+	}
+}
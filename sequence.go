@@ -0,0 +1,35 @@
+package mbuckets
+
+import (
+	"github.com/boltdb/bolt"
+)
+
+// NextID returns the next value of the sequence of the bolt.Bucket specified by this Bucket, see bolt.Bucket.NextSequence
+func (b *Bucket) NextID() (id uint64, err error) {
+	err = b.mutate(OpNextID, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		id, err = bucket.NextSequence()
+		return err
+	})
+
+	return id, err
+}
+
+// InsertNext puts the given value in the bolt.Bucket specified by this Bucket under the next value of its sequence,
+// and returns that value. The key is the 8 byte big-endian encoding of the value, see IDKey.
+func (b *Bucket) InsertNext(value []byte) (id uint64, err error) {
+	err = b.mutate(OpInsertNext, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		id, err = bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		return b.put(bucket, IDKey(id), value)
+	})
+
+	return id, err
+}
+
+// IDKey returns the key under which InsertNext puts the value with the given id, see Uint64Key
+func IDKey(id uint64) []byte {
+	return Uint64Key(id)
+}
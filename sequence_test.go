@@ -0,0 +1,59 @@
+package mbuckets_test
+
+import (
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestNextID(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	t.Log("Retrieving the next id")
+	id, err := bucket.NextID()
+	if err != nil {
+		t.Errorf("Unable to retrieve the next id. Error: %s", err.Error())
+	}
+
+	if id != 1 {
+		t.Errorf("First id %d is not 1", id)
+	}
+
+	values := []string{"value1", "value2", "value3"}
+	for idx, value := range values {
+		t.Logf("Inserting Value: %s under the next id", value)
+		id, err = bucket.InsertNext([]byte(value))
+		if err != nil {
+			t.Errorf("Unable to insert value under the next id. Error: %s", err.Error())
+		}
+
+		if id != uint64(idx+2) {
+			t.Errorf("Id %d does not follow the previous id", id)
+		}
+	}
+
+	items, err := bucket.GetAll()
+	if err != nil {
+		t.Errorf("Unable to retrieve key/value pairs from bucket. Error: %s", err.Error())
+	}
+
+	if len(items) != len(values) {
+		t.Errorf("Number of items %d does not match the number of values inserted", len(items))
+	}
+
+	for idx, item := range items {
+		if string(item.Key) != string(mbuckets.IDKey(uint64(idx+2))) || string(item.Value) != values[idx] {
+			t.Errorf("Item %d does not match the value inserted under its id", idx)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package mbuckets
+
+import (
+	"log"
+	"math/bits"
+
+	"github.com/boltdb/bolt"
+)
+
+// Histogram describes the distribution of a set of sizes in bytes
+type Histogram struct {
+	// Number of sizes
+	Count int
+
+	// Smallest and largest size, zero if there are no sizes
+	Min, Max int
+
+	// Sum of all sizes
+	Total int
+
+	// Buckets[i] counts the sizes that are i bits long, that is 0 for i = 0 and within [2^(i-1), 2^i) otherwise
+	Buckets [32]int
+}
+
+// Mean returns the average size, zero if there are no sizes
+func (h *Histogram) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+
+	return float64(h.Total) / float64(h.Count)
+}
+
+func (h *Histogram) add(size int) {
+	if h.Count == 0 || size < h.Min {
+		h.Min = size
+	}
+
+	if size > h.Max {
+		h.Max = size
+	}
+
+	idx := bits.Len(uint(size))
+	if idx >= len(h.Buckets) {
+		idx = len(h.Buckets) - 1
+	}
+
+	h.Count++
+	h.Total += size
+	h.Buckets[idx]++
+}
+
+// SizeDistribution describes the sizes of the keys and values in a bolt.Bucket
+type SizeDistribution struct {
+	Keys   Histogram
+	Values Histogram
+}
+
+// SizeDistribution walks the key/value pairs of the bolt.Bucket specified by this Bucket, excluding nested bolt.Buckets,
+// and returns the distribution of their sizes
+func (b *Bucket) SizeDistribution() (SizeDistribution, error) {
+	var distribution SizeDistribution
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		return bucket.ForEach(func(k, v []byte) error {
+			if v != nil {
+				distribution.Keys.add(len(k))
+				distribution.Values.add(len(v))
+			}
+			return nil
+		})
+	})
+
+	return distribution, err
+}
+
+// WarnValueSize sets function `fn` to be called, inside the write transaction, for every value larger than limit bytes
+// put through the methods of Bucket. Such values are usually better split into chunks.
+// If `fn` is nil, the warning is logged using the standard logger. A limit of zero turns the warning off.
+func (db *DB) WarnValueSize(limit int, fn func(bucket, key []byte, size int)) {
+	if fn == nil {
+		fn = func(bucket, key []byte, size int) {
+			log.Printf("mbuckets: value of key %s in bucket %s is %d bytes", key, bucket, size)
+		}
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.valueSizeLimit = limit
+	db.valueSizeWarning = fn
+}
+
+// put puts a single key/value pair in bolt.Bucket `bucket` specified by this Bucket, warning about large values
+// and refusing to overwrite keys of a write-once bolt.Bucket
+func (b *Bucket) put(bucket *bolt.Bucket, key, value []byte) error {
+	b.DB.mu.RLock()
+	limit := b.DB.valueSizeLimit
+	warn := b.DB.valueSizeWarning
+	stat := b.DB.txStats[bucket.Tx()]
+	b.DB.mu.RUnlock()
+
+	if limit > 0 && len(value) > limit {
+		warn(b.Name, key, len(value))
+	}
+
+	exists := bucket.Get(key) != nil
+	if exists && isWriteOnce(bucket.Tx(), b.segments()) {
+		return writeOnceError(b.Name, key)
+	}
+
+	err := bucket.Put(key, value)
+	if err != nil {
+		return err
+	}
+
+	if stat != nil {
+		stat.Puts++
+		stat.BytesWritten += len(key) + len(value)
+	}
+
+	if !exists {
+		b.DB.recordKeyChange(bucket.Tx(), b.segments(), key, true)
+	}
+
+	return nil
+}
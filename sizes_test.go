@@ -0,0 +1,56 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSizeDistribution(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	var warnings []string
+	t.Log("Warning about values larger than 100 bytes")
+	db.WarnValueSize(100, func(bucket, key []byte, size int) {
+		warnings = append(warnings, string(key))
+	})
+
+	items := map[string]string{"a": "", "bb": "value", "ccc": string(bytes.Repeat([]byte("v"), 200))}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	if len(warnings) != 1 || warnings[0] != "ccc" {
+		t.Errorf("Warnings %v do not match the values larger than the limit", warnings)
+	}
+
+	t.Log("Retrieving the size distribution")
+	distribution, err := bucket.SizeDistribution()
+	if err != nil {
+		t.Errorf("Unable to retrieve the size distribution. Error: %s", err.Error())
+	}
+
+	keys, values := distribution.Keys, distribution.Values
+	t.Logf("Keys: %+v", keys)
+	t.Logf("Values: %+v", values)
+
+	if keys.Count != 3 || keys.Min != 1 || keys.Max != 3 || keys.Mean() != 2 {
+		t.Error("Key size distribution does not match the keys inserted")
+	}
+
+	if values.Total != 205 || values.Buckets[0] != 1 || values.Buckets[3] != 1 || values.Buckets[8] != 1 {
+		t.Error("Value size distribution does not match the values inserted")
+	}
+}
@@ -0,0 +1,50 @@
+package mbuckets
+
+// Snapshot is a long-lived read-only transaction, so that a sequence of reads through its Buckets
+// sees a single consistent version of the data. A Snapshot must be closed to release the transaction.
+//
+// Writes that need bolt to grow its memory map wait until all open Snapshots are closed, and DB.Close waits for them as well,
+// so Snapshots should not be kept longer than needed. Opening the DB with a bolt.Options.InitialMmapSize
+// larger than the expected database size avoids such waits.
+type Snapshot struct {
+	tx *Tx
+}
+
+// Snapshot starts a read-only transaction and returns a Snapshot holding it
+func (db *DB) Snapshot() (*Snapshot, error) {
+	tx, err := db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{tx}, nil
+}
+
+// Bucket returns the Bucket with the given "/" separated name reading from this Snapshot
+func (s *Snapshot) Bucket(name []byte) *Bucket {
+	return s.tx.Bucket(name)
+}
+
+// BucketString is a convenience wrapper over Bucket for string name
+func (s *Snapshot) BucketString(name string) *Bucket {
+	return s.tx.BucketString(name)
+}
+
+// BucketAt returns the Bucket with the given path segments reading from this Snapshot, see DB.BucketAt
+func (s *Snapshot) BucketAt(path ...[]byte) *Bucket {
+	return s.tx.BucketAt(path...)
+}
+
+// Bind returns a copy of Bucket `b` reading from this Snapshot
+func (s *Snapshot) Bind(b *Bucket) *Bucket {
+	return s.tx.Bind(b)
+}
+
+// Close releases the transaction of this Snapshot. Reads through its Buckets fail with bolt.ErrTxClosed afterwards.
+func (s *Snapshot) Close() error {
+	if s.tx.Tx.DB() == nil {
+		return nil
+	}
+
+	return s.tx.Rollback()
+}
@@ -0,0 +1,90 @@
+package mbuckets_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Log("Creating a new test db with an initial mmap size")
+	fileName := tempFile()
+	db, err := mbuckets.OpenWith(fileName, 0600, &bolt.Options{Timeout: time.Second, InitialMmapSize: 1 << 20})
+	if err != nil {
+		t.Fatalf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer os.Remove(fileName)
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting key/value pairs in bucket")
+	err = bucket.InsertAllString(map[string]string{"key1": "value1", "key2": "value2"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Taking a snapshot")
+	snapshot, err := db.Snapshot()
+	if err != nil {
+		t.Fatalf("Unable to take a snapshot. Error: %s", err.Error())
+	}
+
+	t.Log("Updating key/value pairs after the snapshot")
+	err = bucket.InsertString("key1", "updated")
+	if err != nil {
+		t.Errorf("Unable to update key/value pair. Error: %s", err.Error())
+	}
+
+	err = bucket.InsertString("key3", "value3")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	value, err := snapshot.BucketString("Bucket1").GetString("key1")
+	if err != nil {
+		t.Errorf("Unable to retrieve value from snapshot. Error: %s", err.Error())
+	}
+
+	if value != "value1" {
+		t.Errorf("Snapshot value %s does not match the value at the time of the snapshot", value)
+	}
+
+	items, err := snapshot.Bind(bucket).GetPrefix([]byte("key"))
+	if err != nil {
+		t.Errorf("Unable to retrieve items from snapshot. Error: %s", err.Error())
+	}
+
+	if len(items) != 2 {
+		t.Errorf("Snapshot holds %d items instead of the items at the time of the snapshot", len(items))
+	}
+
+	value, err = bucket.GetString("key1")
+	if err != nil {
+		t.Errorf("Unable to retrieve value. Error: %s", err.Error())
+	}
+
+	if value != "updated" {
+		t.Errorf("Current value %s does not match the updated value", value)
+	}
+
+	t.Log("Closing the snapshot")
+	err = snapshot.Close()
+	if err != nil {
+		t.Errorf("Unable to close snapshot. Error: %s", err.Error())
+	}
+
+	_, err = snapshot.BucketString("Bucket1").GetString("key1")
+	if err != bolt.ErrTxClosed {
+		t.Errorf("Read from a closed snapshot did not fail with ErrTxClosed. Error: %v", err)
+	}
+
+	err = snapshot.Close()
+	if err != nil {
+		t.Errorf("Closing the snapshot twice failed. Error: %s", err.Error())
+	}
+}
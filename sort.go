@@ -0,0 +1,45 @@
+package mbuckets
+
+import (
+	"bytes"
+	"sort"
+)
+
+// ResultSort reports whether Item a must be returned before Item b
+type ResultSort func(a, b Item) bool
+
+var (
+	// ByKey orders Items by key, which is the order of keys in bolt
+	ByKey ResultSort = func(a, b Item) bool {
+		return bytes.Compare(a.Key, b.Key) < 0
+	}
+
+	// ByValueLen orders Items by the length of their values
+	ByValueLen ResultSort = func(a, b Item) bool {
+		return len(a.Value) < len(b.Value)
+	}
+)
+
+// Reverse returns a ResultSort ordering Items in the reverse order of the given one
+func Reverse(less ResultSort) ResultSort {
+	return func(a, b Item) bool {
+		return less(b, a)
+	}
+}
+
+// WithSort sets the order of Items returned by this Bucket and returns a pointer to this Bucket.
+// Items comparing equal keep their key order.
+func (b *Bucket) WithSort(less ResultSort) *Bucket {
+	b.Sort = less
+	return b
+}
+
+func (b *Bucket) sortItems(items []Item) {
+	if b.Sort == nil {
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return b.Sort(items[i], items[j])
+	})
+}
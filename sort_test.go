@@ -0,0 +1,64 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestResultSort(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := map[string]string{
+		"key1": "value111",
+		"key2": "v",
+		"key3": "value3",
+	}
+
+	t.Log("Inserting key/value pairs")
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	sorts := map[string]struct {
+		less     mbuckets.ResultSort
+		expected []string
+	}{
+		"key":                  {mbuckets.ByKey, []string{"key1", "key2", "key3"}},
+		"reverse key":          {mbuckets.Reverse(mbuckets.ByKey), []string{"key3", "key2", "key1"}},
+		"value length":         {mbuckets.ByValueLen, []string{"key2", "key3", "key1"}},
+		"reverse value length": {mbuckets.Reverse(mbuckets.ByValueLen), []string{"key1", "key3", "key2"}},
+	}
+
+	for name, sort := range sorts {
+		t.Logf("Retrieving items sorted by %s", name)
+		results, err := bucket.WithSort(sort.less).GetPrefix([]byte("key"))
+		if err != nil {
+			t.Errorf("Unable to retrieve items from bucket. Error: %s", err.Error())
+		}
+
+		if len(results) != len(sort.expected) {
+			t.Errorf("Number of items sorted by %s does not match the number of items inserted", name)
+			continue
+		}
+
+		for idx, result := range results {
+			if !bytes.Equal(result.Key, []byte(sort.expected[idx])) {
+				t.Errorf("Items are not sorted by %s", name)
+				break
+			}
+		}
+	}
+}
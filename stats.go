@@ -0,0 +1,214 @@
+package mbuckets
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+// EstimateCount returns an approximate number of key/value pairs in the bolt.Bucket specified by this Bucket,
+// excluding nested bolt.Buckets and their keys.
+//
+// The count is taken from bolt's page statistics, see bolt.Bucket.Stats, which visit the pages of the bolt.Bucket
+// without reading its keys and values. As the statistics include the keys of nested buckets, and do not reflect
+// the uncommitted writes of a writable Tx, a bolt.Bucket holding nested bolt.Buckets, or read within a writable Tx,
+// is counted with a cursor walk over its keys instead.
+func (b *Bucket) EstimateCount() (int, error) {
+	var count int
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		if stats := bucket.Stats(); stats.BucketN == 1 && !tx.Writable() {
+			count = stats.KeyN
+			return nil
+		}
+
+		count = countFrom(bucket.Cursor(), nil, func([]byte) bool { return true })
+		return nil
+	})
+
+	return count, err
+}
+
+// Count returns the number of key/value pairs in the bolt.Bucket specified by this Bucket, excluding nested bolt.Buckets.
+//
+// The count is taken from bolt's page statistics when the bolt.Bucket has no nested buckets,
+// and from a cursor walk over its keys otherwise. Values are never copied.
+func (b *Bucket) Count() (int, error) {
+	var count int
+	if b.withIndexedKeys(func(keys [][]byte) {
+		count = len(keys)
+	}) {
+		return count, nil
+	}
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		if stats := bucket.Stats(); stats.BucketN == 1 {
+			count = stats.KeyN
+			return nil
+		}
+
+		count = countFrom(bucket.Cursor(), nil, func([]byte) bool { return true })
+		return nil
+	})
+
+	return count, err
+}
+
+// CountPrefix returns the number of key/value pairs in the bolt.Bucket specified by this Bucket with the given prefix,
+// excluding nested bolt.Buckets. Values are never copied.
+func (b *Bucket) CountPrefix(prefix []byte) (int, error) {
+	var count int
+	if b.withIndexedKeys(func(keys [][]byte) {
+		count = len(prefixKeys(keys, prefix))
+	}) {
+		return count, nil
+	}
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		count = countFrom(bucket.Cursor(), prefix, func(k []byte) bool {
+			return bytes.HasPrefix(k, prefix)
+		})
+		return nil
+	})
+
+	return count, err
+}
+
+// CountRange returns the number of key/value pairs in the bolt.Bucket specified by this Bucket within the given range,
+// excluding nested bolt.Buckets. Values are never copied.
+func (b *Bucket) CountRange(min, max []byte) (int, error) {
+	var count int
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		count = countFrom(bucket.Cursor(), min, func(k []byte) bool {
+			return bytes.Compare(k, max) <= 0
+		})
+		return nil
+	})
+
+	return count, err
+}
+
+// countFrom counts the key/value pairs starting at `start` for which function `match` holds,
+// stopping at the first key that does not match
+func countFrom(cursor *bolt.Cursor, start []byte, match func([]byte) bool) int {
+	count := 0
+	for k, v := cursor.Seek(start); k != nil && match(k); k, v = cursor.Next() {
+		if v != nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Stats returns bolt's page and key statistics of the bolt.Bucket specified by this Bucket,
+// including those of nested buckets, see bolt.Bucket.Stats
+func (b *Bucket) Stats() (bolt.BucketStats, error) {
+	var stats bolt.BucketStats
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		stats = bucket.Stats()
+		return nil
+	})
+
+	return stats, err
+}
+
+// StatsTree returns bolt's page and key statistics of every bolt.Bucket in this DB, read in a single transaction
+// and keyed by the complete hierarchial bucket name using "/" as the separator.
+// The statistics of a bolt.Bucket include those of the bolt.Buckets nested under it.
+func (db *DB) StatsTree() (map[string]bolt.BucketStats, error) {
+	separator := []byte("/")
+	tree := make(map[string]bolt.BucketStats)
+
+	var walk func(path [][]byte, bucket *bolt.Bucket)
+	walk = func(path [][]byte, bucket *bolt.Bucket) {
+		tree[string(db.joinNames(path, separator))] = bucket.Stats()
+
+		bucket.ForEach(func(k, v []byte) error {
+			if v == nil {
+				walk(append(path[:len(path):len(path)], k), bucket.Bucket(k))
+			}
+			return nil
+		})
+	}
+
+	err := db.view(nil, func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+			if !isMetaBucket(name) {
+				walk([][]byte{name}, bucket)
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// EstimateBytes returns an approximate number of bytes used by the bolt.Bucket specified by this Bucket.
+//
+// The estimate is the sum of the in-use bytes of all branch, leaf and inline pages of the bolt.Bucket,
+// including those of nested buckets.
+func (b *Bucket) EstimateBytes() (int, error) {
+	var size int
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		stats := bucket.Stats()
+		size = stats.BranchInuse + stats.LeafInuse + stats.InlineBucketInuse
+		return nil
+	})
+
+	return size, err
+}
+
+// SplitPoints returns up to n-1 keys dividing the key/value pairs in the bolt.Bucket specified by this Bucket
+// into n ranges of similar size.
+//
+// The first range holds the keys before the first split point, and each following range starts at its split point.
+// Fewer split points are returned when the bolt.Bucket holds fewer than n keys.
+func (b *Bucket) SplitPoints(n int) ([][]byte, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("Invalid number of ranges: %d", n)
+	}
+
+	var points [][]byte
+
+	err := b.View(func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		cursor := bucket.Cursor()
+
+		count := 0
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			if v != nil {
+				count++
+			}
+		}
+
+		idx, next := 0, 1
+		for k, v := cursor.First(); k != nil && next < n; k, v = cursor.Next() {
+			if v == nil {
+				continue
+			}
+
+			if idx > 0 && idx >= next*count/n {
+				point := make([]byte, len(k))
+				copy(point, k)
+				points = append(points, point)
+
+				for next < n && idx >= next*count/n {
+					next++
+				}
+			}
+			idx++
+		}
+
+		return nil
+	})
+
+	return points, err
+}
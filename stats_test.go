@@ -0,0 +1,307 @@
+package mbuckets_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestEstimateCountBytes(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := make(map[string]string, 100)
+	for i := 0; i < 100; i++ {
+		items[fmt.Sprintf("key%03d", i)] = fmt.Sprintf("value%03d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Estimating number of keys in bucket")
+	count, err := bucket.EstimateCount()
+	if err != nil {
+		t.Errorf("Unable to estimate number of keys in bucket. Error: %s", err.Error())
+	}
+
+	t.Logf("Estimated number of keys: %d", count)
+	if count != len(items) {
+		t.Error("Estimated number of keys does not match the number of key/value pairs inserted")
+	}
+
+	t.Log("Estimating size of bucket")
+	size, err := bucket.EstimateBytes()
+	if err != nil {
+		t.Errorf("Unable to estimate size of bucket. Error: %s", err.Error())
+	}
+
+	t.Logf("Estimated size: %d bytes", size)
+	if size < len(items)*12 {
+		t.Error("Estimated size is smaller than the size of the key/value pairs inserted")
+	}
+}
+
+func TestSplitPoints(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := make(map[string]string, 100)
+	for i := 0; i < 100; i++ {
+		items[fmt.Sprintf("key%03d", i)] = fmt.Sprintf("value%03d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Retrieving split points for 4 ranges")
+	points, err := bucket.SplitPoints(4)
+	if err != nil {
+		t.Errorf("Unable to retrieve split points. Error: %s", err.Error())
+	}
+
+	expected := []string{"key025", "key050", "key075"}
+	if fmt.Sprintf("%s", points) != fmt.Sprint(expected) {
+		t.Errorf("Split points %s do not match the expected split points %s", points, expected)
+	}
+
+	t.Log("Retrieving split points for more ranges than keys")
+	points, err = bucket.SplitPoints(1000)
+	if err != nil {
+		t.Errorf("Unable to retrieve split points. Error: %s", err.Error())
+	}
+
+	if len(points) != len(items)-1 {
+		t.Error("Number of split points does not match the number of keys")
+	}
+}
+
+func TestCount(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := make(map[string]string, 100)
+	for i := 0; i < 100; i++ {
+		items[fmt.Sprintf("key%03d", i)] = fmt.Sprintf("value%03d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Counting keys in bucket without nested buckets")
+	count, err := bucket.Count()
+	if err != nil {
+		t.Errorf("Unable to count keys in bucket. Error: %s", err.Error())
+	}
+
+	if count != len(items) {
+		t.Errorf("Number of keys %d does not match the number of key/value pairs inserted", count)
+	}
+
+	t.Log("Inserting key/value pairs in a nested bucket")
+	err = db.BucketString("Bucket1/Bucket2").InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in nested bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Counting keys in bucket with nested buckets")
+	count, err = bucket.Count()
+	if err != nil {
+		t.Errorf("Unable to count keys in bucket. Error: %s", err.Error())
+	}
+
+	if count != len(items) {
+		t.Errorf("Number of keys %d includes the keys of nested buckets", count)
+	}
+}
+
+func TestCountPrefixRange(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Bucket1")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	items := make(map[string]string, 100)
+	for i := 0; i < 100; i++ {
+		items[fmt.Sprintf("key%03d", i)] = fmt.Sprintf("value%03d", i)
+	}
+
+	t.Logf("Inserting %d key/value pairs", len(items))
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Counting keys with prefix: key04")
+	count, err := bucket.CountPrefix([]byte("key04"))
+	if err != nil {
+		t.Errorf("Unable to count keys with prefix in bucket. Error: %s", err.Error())
+	}
+
+	if count != 10 {
+		t.Errorf("Number of keys %d does not match the number of keys with prefix", count)
+	}
+
+	t.Log("Counting keys in range: key010 to key029")
+	count, err = bucket.CountRange([]byte("key010"), []byte("key029"))
+	if err != nil {
+		t.Errorf("Unable to count keys in range in bucket. Error: %s", err.Error())
+	}
+
+	if count != 20 {
+		t.Errorf("Number of keys %d does not match the number of keys in range", count)
+	}
+}
+
+func TestStatsTree(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	for _, name := range []string{"Root/Child1", "Root/Child2/Leaf", "Other"} {
+		t.Logf("Inserting key/value pairs in bucket: %s", name)
+		err = db.BucketString(name).InsertAllString(map[string]string{"key1": "value1", "key2": "value2"})
+		if err != nil {
+			t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Retrieving stats of bucket: Root/Child2")
+	stats, err := db.BucketString("Root/Child2").Stats()
+	if err != nil {
+		t.Errorf("Unable to retrieve bucket stats. Error: %s", err.Error())
+	}
+
+	t.Logf("Bucket stats: %+v", stats)
+	if stats.BucketN != 2 || stats.KeyN != 3 {
+		t.Errorf("Bucket stats do not match the nested bucket and its keys: BucketN %d, KeyN %d", stats.BucketN, stats.KeyN)
+	}
+
+	t.Log("Retrieving stats tree of db")
+	tree, err := db.StatsTree()
+	if err != nil {
+		t.Errorf("Unable to retrieve stats tree. Error: %s", err.Error())
+	}
+
+	expected := []string{"Root", "Root/Child1", "Root/Child2", "Root/Child2/Leaf", "Other"}
+	if len(tree) != len(expected) {
+		t.Errorf("Number of buckets in stats tree %d does not match expected %d", len(tree), len(expected))
+	}
+
+	for _, name := range expected {
+		if _, ok := tree[name]; !ok {
+			t.Errorf("Stats tree is missing bucket: %s", name)
+		}
+	}
+
+	if tree["Root/Child2/Leaf"].KeyN != 2 {
+		t.Errorf("Stats tree KeyN %d of bucket Root/Child2/Leaf does not match the keys inserted", tree["Root/Child2/Leaf"].KeyN)
+	}
+}
+
+func TestEstimateCountNested(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Large")
+
+	t.Log("Inserting 20000 key/value pairs spanning many pages")
+	items := make(map[string]string, 20000)
+	for i := 0; i < 20000; i++ {
+		items[fmt.Sprintf("key%06d", i)] = fmt.Sprintf("value%06d", i)
+	}
+
+	err = bucket.InsertAllString(items)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+	}
+
+	t.Log("Inserting key/value pairs into a nested bucket")
+	nested := make(map[string]string, 5000)
+	for i := 0; i < 5000; i++ {
+		nested[fmt.Sprintf("key%06d", i)] = fmt.Sprintf("value%06d", i)
+	}
+
+	err = db.BucketString("Large/Nested").InsertAllString(nested)
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs in nested bucket. Error: %s", err.Error())
+	}
+
+	count, err := bucket.EstimateCount()
+	if err != nil {
+		t.Errorf("Unable to estimate number of keys in bucket. Error: %s", err.Error())
+	}
+
+	t.Logf("Estimated number of keys: %d", count)
+	if count != 20000 {
+		t.Errorf("Estimated number of keys %d includes the keys of the nested bucket", count)
+	}
+
+	t.Log("Estimating number of keys within a Tx after inserting a key")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		bucket := tx.BucketString("Large")
+		err := bucket.InsertString("key999999", "value")
+		if err != nil {
+			return err
+		}
+
+		count, err = bucket.EstimateCount()
+		return err
+	})
+
+	if err != nil || count != 20001 {
+		t.Errorf("Estimated number of keys %d within a Tx does not include its insert. Error: %v", count, err)
+	}
+}
@@ -0,0 +1,51 @@
+package mbuckets
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// TimeKey encodes the given time as the Int64Key of its Unix time in nanoseconds, so that keys sort in time order.
+// Times outside the years 1678 to 2262 can not be represented, see time.Time.UnixNano.
+func TimeKey(t time.Time) []byte {
+	return Int64Key(t.UnixNano())
+}
+
+// ParseTimeKey decodes a key encoded by TimeKey
+func ParseTimeKey(key []byte) (time.Time, error) {
+	n, err := ParseInt64Key(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, n), nil
+}
+
+// InsertAt puts the given value in the bolt.Bucket specified by this Bucket under the TimeKey of the given time.
+// A value inserted earlier at the same nanosecond is replaced.
+func (b *Bucket) InsertAt(t time.Time, value []byte) error {
+	return b.Insert(TimeKey(t), value)
+}
+
+// GetTimeRange retrieves all the key/value pairs with keys encoded by TimeKey between the given times, inclusive,
+// from the bolt.Bucket specified by this Bucket
+func (b *Bucket) GetTimeRange(from, to time.Time) ([]Item, error) {
+	return b.GetRange(TimeKey(from), TimeKey(to))
+}
+
+// DeleteOlderThan removes all the keys encoded by TimeKey before the given time from the bolt.Bucket specified by this Bucket
+// in a single transaction, and returns the number of keys removed
+func (b *Bucket) DeleteOlderThan(t time.Time) (count int, err error) {
+	cutoff := TimeKey(t)
+
+	err = b.mutate(OpDeleteRange, nil, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+		count, err = b.deleteFrom(bucket, b.segments(), nil, func(k []byte) bool {
+			return bytes.Compare(k, cutoff) < 0
+		})
+		return err
+	})
+
+	return count, err
+}
@@ -0,0 +1,60 @@
+package mbuckets_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestTimeKeys(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucketName := []byte("Events")
+	t.Logf("Creating bucket: %s", bucketName)
+	bucket := db.Bucket(bucketName)
+
+	start := time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		err = bucket.InsertAt(start.Add(time.Duration(i)*24*time.Hour), []byte{byte(i)})
+		if err != nil {
+			t.Errorf("Unable to insert value at time in bucket. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Retrieving values between day 2 and day 4")
+	items, err := bucket.GetTimeRange(start.Add(2*24*time.Hour), start.Add(4*24*time.Hour))
+	if err != nil {
+		t.Errorf("Unable to retrieve time range from bucket. Error: %s", err.Error())
+	}
+
+	if len(items) != 3 || items[0].Value[0] != 2 || items[2].Value[0] != 4 {
+		t.Errorf("Items %v do not match the values inserted in time range", items)
+	}
+
+	at, err := mbuckets.ParseTimeKey(items[0].Key)
+	if err != nil || !at.Equal(start.Add(2*24*time.Hour)) {
+		t.Errorf("Decoded time %s does not match the time inserted. Error: %v", at, err)
+	}
+
+	t.Log("Deleting values older than day 5")
+	count, err := bucket.DeleteOlderThan(start.Add(5 * 24 * time.Hour))
+	if err != nil {
+		t.Errorf("Unable to delete old values from bucket. Error: %s", err.Error())
+	}
+
+	if count != 5 {
+		t.Errorf("Number of values deleted %d does not match the number of values older than cutoff", count)
+	}
+
+	first, err := bucket.First()
+	if err != nil || first.Value[0] != 5 {
+		t.Errorf("Oldest remaining value %v is not at the cutoff. Error: %v", first.Value, err)
+	}
+}
@@ -0,0 +1,78 @@
+package mbuckets
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimeoutError is returned when a write transaction exceeds the timeout set by SetWriteTimeout, after it has been rolled back
+type TimeoutError struct {
+	// Complete hierarchial name of the Bucket the transaction was started for, or the operation exceeding the timeout was made on,
+	// nil for DB level transactions
+	Bucket []byte
+
+	// The exceeded timeout
+	Timeout time.Duration
+
+	// Time elapsed since the start of the transaction when the timeout was detected
+	Elapsed time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("Write transaction on bucket %s exceeded timeout %s after %s", e.Bucket, e.Timeout, e.Elapsed)
+}
+
+// Unwrap returns context.DeadlineExceeded so that errors.Is matches a TimeoutError
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// SetWriteTimeout sets the maximum duration of the write transactions run by Update, UpdateTx and the Bucket operations.
+// A transaction exceeding it is rolled back, and a *TimeoutError is returned. Zero, the default, disables the timeout.
+//
+// A running function can not be interrupted, so the timeout is detected when the function passed to the transaction returns,
+// or when it makes an operation through a Bucket bound to the Tx, which then fails with a *TimeoutError.
+// Functions passed to UpdateTx should watch Tx.Context to stop long running work once the deadline expires;
+// returning its error rolls the transaction back with a *TimeoutError as well.
+// Transactions started by Begin or Batch are not bounded.
+func (db *DB) SetWriteTimeout(timeout time.Duration) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.writeTimeout = timeout
+}
+
+// checkTimeout returns a *TimeoutError if a write transaction on the bucket with the given name,
+// started at `start`, has exceeded the given timeout
+func (db *DB) checkTimeout(name []byte, start time.Time, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	if elapsed := db.now().Sub(start); elapsed > timeout {
+		return &TimeoutError{name, timeout, elapsed}
+	}
+
+	return nil
+}
+
+// Deadline returns the time by which this Tx must finish, as measured by the Clock of its DB, see SetWriteTimeout.
+// It returns false if the Tx is not bounded by a timeout.
+func (tx *Tx) Deadline() (time.Time, bool) {
+	if tx.timeout <= 0 {
+		return time.Time{}, false
+	}
+
+	return tx.start.Add(tx.timeout), true
+}
+
+// Context returns a context.Context that is cancelled once the deadline of this Tx expires, or once the Tx finishes.
+// Only the Context of a Tx run by UpdateTx is cancelled, and its deadline follows the wall clock rather than the Clock of the DB.
+func (tx *Tx) Context() context.Context {
+	if tx.ctx == nil {
+		return context.Background()
+	}
+
+	return tx.ctx
+}
@@ -0,0 +1,159 @@
+package mbuckets_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestSetWriteTimeout(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	clock := &fakeClock{}
+	db.SetClock(clock)
+	db.SetWriteTimeout(10 * time.Second)
+
+	advance := func(d time.Duration) {
+		clock.mu.Lock()
+		clock.now = clock.now.Add(d)
+		clock.mu.Unlock()
+	}
+
+	t.Log("Inserting key/value pair within the timeout")
+	err = db.BucketString("Data").InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	t.Log("Running a transaction exceeding the timeout")
+	err = db.Update(func(tx *bolt.Tx) error {
+		advance(time.Minute)
+		return tx.Bucket([]byte("Data")).Put([]byte("key2"), []byte("value2"))
+	})
+
+	var timeoutErr *mbuckets.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Slow transaction did not fail with a TimeoutError. Error: %v", err)
+	} else if timeoutErr.Timeout != 10*time.Second || timeoutErr.Elapsed != time.Minute {
+		t.Errorf("TimeoutError %+v does not match the timeout and elapsed time", timeoutErr)
+	}
+
+	t.Log("Running operations on a bound bucket after the timeout")
+	inserts := 0
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		bucket := tx.BucketString("Data")
+		advance(time.Minute)
+
+		err := bucket.InsertString("key3", "value3")
+		if err == nil {
+			inserts++
+		}
+
+		// The timeout is detected even if the error is ignored
+		return nil
+	})
+
+	if inserts != 0 {
+		t.Errorf("Insert succeeded after the timeout")
+	}
+
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Slow transaction did not fail with a TimeoutError. Error: %v", err)
+	}
+
+	keys, err := db.BucketString("Data").GetAllKeys()
+	if err != nil {
+		t.Errorf("Unable to get keys. Error: %s", err.Error())
+	}
+
+	if len(keys) != 1 || string(keys[0]) != "key1" {
+		t.Errorf("Keys %q include writes of timed out transactions", keys)
+	}
+
+	t.Log("Disabling the timeout")
+	db.SetWriteTimeout(0)
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		advance(time.Minute)
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Transaction failed without a timeout. Error: %s", err.Error())
+	}
+}
+
+func TestTxContext(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Running a transaction without a timeout")
+	var ctx context.Context
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		if _, ok := tx.Deadline(); ok {
+			t.Errorf("Tx has a deadline without a timeout")
+		}
+
+		ctx = tx.Context()
+		return ctx.Err()
+	})
+
+	if err != nil {
+		t.Errorf("Transaction failed without a timeout. Error: %s", err.Error())
+	}
+
+	if ctx.Err() == nil {
+		t.Errorf("Context of the Tx was not cancelled once it finished")
+	}
+
+	db.SetWriteTimeout(50 * time.Millisecond)
+
+	t.Log("Running a transaction waiting for its deadline")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		if deadline, ok := tx.Deadline(); !ok || deadline.IsZero() {
+			t.Errorf("Tx has no deadline with a timeout")
+		}
+
+		err := tx.BucketString("Data").InsertString("key1", "value1")
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-tx.Context().Done():
+			return tx.Context().Err()
+		case <-time.After(5 * time.Second):
+			t.Errorf("Context of the Tx was not cancelled at its deadline")
+			return nil
+		}
+	})
+
+	var timeoutErr *mbuckets.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Transaction stopped at its deadline did not fail with a TimeoutError. Error: %v", err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TimeoutError does not match context.DeadlineExceeded. Error: %v", err)
+	}
+
+	_, err = db.BucketString("Data").GetString("key1")
+	if !errors.Is(err, mbuckets.ErrBucketNotFound) {
+		t.Errorf("Write of the timed out transaction was committed. Error: %v", err)
+	}
+}
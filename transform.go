@@ -0,0 +1,67 @@
+package mbuckets
+
+import (
+	"bytes"
+)
+
+// Transform rewrites the records of an import that belong to the matching buckets before they are written,
+// so that exports of an older layout can be imported into a new one
+type Transform struct {
+	// Pattern selects the buckets the Transform applies to. It is matched like ConflictRule.Pattern,
+	// but against the bucket path relative to the importing Bucket, which is empty for the importing Bucket itself.
+	// A nil Pattern matches all buckets.
+	Pattern []byte
+
+	// Rename, if not nil, replaces the relative path of the matching bucket, split on the separator of the importing Bucket.
+	// An empty Rename moves the records into the importing Bucket itself.
+	Rename []byte
+
+	// Key, if not nil, returns the key to write for a key in the matching bucket, or nil to drop the key/value pair
+	Key func(bucket, key []byte) ([]byte, error)
+
+	// Value, if not nil, returns the value to write for a key/value pair in the matching bucket
+	Value func(bucket, key, value []byte) ([]byte, error)
+}
+
+// transform applies the first Transform matching the bucket of the given record,
+// and reports false if the record is dropped
+func (options *ImportOptions) transform(separator []byte, rec record) (record, bool, error) {
+	name := bytes.Join(rec.Bucket, separator)
+
+	for _, transform := range options.Transforms {
+		if transform.Pattern != nil && !matchBucketName(transform.Pattern, name, separator) {
+			continue
+		}
+
+		if transform.Rename != nil {
+			rec.Bucket = nil
+			if len(transform.Rename) > 0 {
+				rec.Bucket = bytes.Split(transform.Rename, separator)
+			}
+		}
+
+		if len(rec.Key) == 0 {
+			return rec, len(rec.Bucket) > 0, nil
+		}
+
+		if transform.Key != nil {
+			key, err := transform.Key(name, rec.Key)
+			if err != nil || key == nil {
+				return rec, false, err
+			}
+			rec.Key = key
+		}
+
+		if transform.Value != nil {
+			value, err := transform.Value(name, rec.Key, rec.Value)
+			if err != nil {
+				return rec, false, err
+			}
+			rec.Value = value
+		}
+
+		return rec, true, nil
+	}
+
+	return rec, true, nil
+}
@@ -0,0 +1,102 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestImportTransforms(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	for name, items := range map[string]map[string]string{
+		"Old/users":    {"id:1": "alice", "id:2": "bob", "tmp:3": "scratch"},
+		"Old/settings": {"theme": "dark"},
+	} {
+		t.Logf("Inserting key/value pairs in bucket: %s", name)
+		err = db.BucketString(name).InsertAllString(items)
+		if err != nil {
+			t.Errorf("Unable to insert key/value pairs in bucket. Error: %s", err.Error())
+		}
+	}
+
+	var buf bytes.Buffer
+	t.Log("Exporting items")
+	err = db.BucketString("Old").ExportItems(&buf, mbuckets.FormatNDJSON)
+	if err != nil {
+		t.Errorf("Unable to export items from bucket. Error: %s", err.Error())
+	}
+
+	options := &mbuckets.ImportOptions{
+		Transforms: []mbuckets.Transform{
+			{
+				Pattern: []byte("users"),
+				Rename:  []byte("accounts/v2"),
+				Key: func(bucket, key []byte) ([]byte, error) {
+					if !bytes.HasPrefix(key, []byte("id:")) {
+						return nil, nil
+					}
+					return bytes.TrimPrefix(key, []byte("id:")), nil
+				},
+				Value: func(bucket, key, value []byte) ([]byte, error) {
+					return []byte(strings.ToUpper(string(value))), nil
+				},
+			},
+		},
+	}
+
+	t.Log("Importing items with transforms")
+	_, err = db.BucketString("New").ImportItemsWith(&buf, mbuckets.FormatNDJSON, options)
+
+	if err != nil {
+		t.Errorf("Unable to import items in bucket. Error: %s", err.Error())
+	}
+
+	items, err := db.BucketString("New/accounts/v2").GetAll()
+	if err != nil {
+		t.Errorf("Unable to retrieve items from renamed bucket. Error: %s", err.Error())
+	}
+
+	for _, item := range items {
+		t.Logf("Imported key: %s, value: %s", item.Key, item.Value)
+	}
+
+	if len(items) != 2 || string(items[0].Key) != "1" || string(items[0].Value) != "ALICE" || string(items[1].Key) != "2" {
+		t.Error("Items in renamed bucket do not match the transformed items")
+	}
+
+	exists, err := db.BucketString("New").BucketExists()
+	if err != nil {
+		t.Errorf("Unable to check bucket. Error: %s", err.Error())
+	}
+
+	if !exists {
+		t.Error("Importing bucket was not created")
+	}
+
+	exists, err = db.BucketString("New/users").BucketExists()
+	if err != nil {
+		t.Errorf("Unable to check bucket. Error: %s", err.Error())
+	}
+
+	if exists {
+		t.Error("Renamed bucket was imported under its old name")
+	}
+
+	value, err := db.BucketString("New/settings").GetString("theme")
+	if err != nil {
+		t.Errorf("Unable to retrieve untransformed value. Error: %s", err.Error())
+	}
+
+	if value != "dark" {
+		t.Errorf("Untransformed value %s does not match the exported value", value)
+	}
+}
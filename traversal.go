@@ -0,0 +1,83 @@
+package mbuckets
+
+import (
+	"bytes"
+	"sort"
+)
+
+// TraversalStat describes the bolt.Bucket lookups made to reach a bucket path
+type TraversalStat struct {
+	// Complete hierarchial name of the Bucket
+	Bucket []byte
+
+	// Number of transactions started for the Bucket
+	Operations int
+
+	// Total number of nested bolt.Buckets looked up to reach the Bucket
+	Hops int
+}
+
+// Mean returns the average number of hops per operation
+func (s *TraversalStat) Mean() float64 {
+	if s.Operations == 0 {
+		return 0
+	}
+
+	return float64(s.Hops) / float64(s.Operations)
+}
+
+// TrackTraversal sets whether the number of bolt.Bucket lookups made by the transactions of every Bucket is recorded.
+// Enabling tracking drops previously recorded statistics.
+func (db *DB) TrackTraversal(enabled bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.traversal = nil
+	if enabled {
+		db.traversal = make(map[string]*TraversalStat)
+	}
+}
+
+// TraversalStats returns the statistics recorded since tracking was enabled, ordered by bucket name
+func (db *DB) TraversalStats() []TraversalStat {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	stats := make([]TraversalStat, 0, len(db.traversal))
+	for _, stat := range db.traversal {
+		stats = append(stats, *stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return bytes.Compare(stats[i].Bucket, stats[j].Bucket) < 0
+	})
+
+	return stats
+}
+
+// traversed records an operation on the bucket with the given name that looked up the given number of bolt.Buckets
+func (db *DB) traversed(name []byte, hops int) {
+	db.mu.RLock()
+	enabled := db.traversal != nil
+	db.mu.RUnlock()
+
+	if !enabled {
+		return
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.traversal == nil {
+		return
+	}
+
+	stat, ok := db.traversal[string(name)]
+	if !ok {
+		stat = &TraversalStat{Bucket: append([]byte(nil), name...)}
+		db.traversal[string(name)] = stat
+	}
+
+	stat.Operations++
+	stat.Hops += hops
+}
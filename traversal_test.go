@@ -0,0 +1,57 @@
+package mbuckets_test
+
+import (
+	"testing"
+)
+
+func TestTraversalStats(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Enabling traversal tracking")
+	db.TrackTraversal(true)
+
+	shallow := db.BucketString("Bucket1")
+	deep := db.BucketString("Bucket1/Bucket2/Bucket3")
+
+	for _, bucket := range []interface {
+		InsertString(key, value string) error
+		GetString(key string) (string, error)
+	}{shallow, deep} {
+		err = bucket.InsertString("key1", "value1")
+		if err != nil {
+			t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+		}
+
+		_, err = bucket.GetString("key1")
+		if err != nil {
+			t.Errorf("Unable to retrieve value from bucket. Error: %s", err.Error())
+		}
+	}
+
+	stats := db.TraversalStats()
+	for _, stat := range stats {
+		t.Logf("Bucket: %s, Operations: %d, Hops: %d, Mean: %.1f", stat.Bucket, stat.Operations, stat.Hops, stat.Mean())
+	}
+
+	if len(stats) != 2 || string(stats[1].Bucket) != "Bucket1/Bucket2/Bucket3" || stats[1].Operations != 2 || stats[1].Mean() != 3 {
+		t.Error("Traversal statistics do not match the operations performed")
+	}
+
+	t.Log("Disabling traversal tracking")
+	db.TrackTraversal(false)
+
+	_, err = shallow.GetString("key1")
+	if err != nil {
+		t.Errorf("Unable to retrieve value from bucket. Error: %s", err.Error())
+	}
+
+	if len(db.TraversalStats()) != 0 {
+		t.Error("Traversal statistics were recorded with tracking disabled")
+	}
+}
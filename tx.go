@@ -0,0 +1,317 @@
+package mbuckets
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Tx is a bolt.Tx shared by all operations on the Buckets returned by its Bucket methods, see DB.UpdateTx and DB.ViewTx.
+//
+// A Tx and its Buckets must only be used within the function it was passed to.
+type Tx struct {
+	*bolt.Tx
+
+	db *DB
+
+	// Functions run after the transaction has been committed
+	committed []func()
+
+	// Calls made through the Buckets of this Tx, and the hooks reporting them, see OnCommit and OnRollback
+	mutations     []Mutation
+	commitHooks   []func([]Mutation)
+	rollbackHooks []func([]Mutation, error)
+
+	// Events of the calls made through the Buckets of this Tx, emitted once it commits
+	events []Event
+
+	// Start of the transaction and the timeout bounding it, see SetWriteTimeout
+	start   time.Time
+	timeout time.Duration
+
+	// Context of the transaction, cancelled once its deadline expires or it finishes, see Context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// UpdateTx executes function `fn` within a read-write Tx, so that the operations on its Buckets are applied atomically.
+// All of them are rolled back if function `fn` returns an error.
+//
+// As with Update, the transaction is retried once if bolt could not grow or remap the database file,
+// so function `fn` must be idempotent.
+func (db *DB) UpdateTx(fn func(*Tx) error) error {
+	var tx *Tx
+
+	err := db.update(nil, func(boltTx *bolt.Tx) error {
+		if tx != nil {
+			db.finishKeyChanges(tx.Tx, false)
+			tx.cancel()
+		}
+
+		tx = &Tx{Tx: boltTx, db: db, start: db.now()}
+
+		db.mu.RLock()
+		tx.timeout = db.writeTimeout
+		db.mu.RUnlock()
+
+		if tx.timeout > 0 {
+			tx.ctx, tx.cancel = context.WithTimeout(context.Background(), tx.timeout)
+		} else {
+			tx.ctx, tx.cancel = context.WithCancel(context.Background())
+		}
+
+		return fn(tx)
+	})
+
+	if tx != nil {
+		if hookErr := tx.finish(err == nil, err); err == nil {
+			err = hookErr
+		}
+	}
+
+	return err
+}
+
+// Begin starts a read-write Tx if `writable` is set, or a read-only Tx otherwise, see bolt.DB.Begin.
+// The Tx must be closed with Commit or Rollback. Only one read-write Tx can be open at a time,
+// so Begin blocks while another one is open.
+func (db *DB) Begin(writable bool) (*Tx, error) {
+	if db.IsClosed() {
+		return nil, ErrDBClosed
+	}
+
+	tx, err := db.DB.Begin(writable)
+	if err != nil {
+		return nil, closedError(err)
+	}
+
+	return &Tx{Tx: tx, db: db}, nil
+}
+
+// Commit writes all changes made through this Tx to disk, see bolt.Tx.Commit
+func (tx *Tx) Commit() error {
+	if tx.Tx.DB() == nil {
+		return bolt.ErrTxClosed
+	}
+
+	if !tx.Writable() {
+		return tx.Tx.Commit()
+	}
+
+	err := tx.Tx.Commit()
+	if hookErr := tx.finish(err == nil, err); err == nil {
+		err = hookErr
+	}
+	return err
+}
+
+// Rollback discards all changes made through this Tx, see bolt.Tx.Rollback.
+// The hooks set by OnRollback are called with a nil error.
+func (tx *Tx) Rollback() error {
+	if tx.Tx.DB() == nil {
+		return bolt.ErrTxClosed
+	}
+
+	err := tx.Tx.Rollback()
+	if !tx.Writable() {
+		return err
+	}
+
+	if hookErr := tx.finish(false, nil); err == nil {
+		err = hookErr
+	}
+	return err
+}
+
+// finish runs the functions and hooks of this Tx after it has been committed,
+// or its rollback hooks after it has been rolled back with the given error, nil for an explicit Rollback.
+// All hooks are called, and the first error returned by one of them is returned, see DB.callHook.
+func (tx *Tx) finish(committed bool, err error) error {
+	var hookErr error
+	keep := func(err error) {
+		if hookErr == nil {
+			hookErr = err
+		}
+	}
+
+	if tx.cancel != nil {
+		tx.cancel()
+	}
+
+	tx.db.finishKeyChanges(tx.Tx, false)
+
+	if !committed {
+		for _, hook := range tx.rollbackHooks {
+			keep(tx.db.callHook(nil, func() {
+				hook(tx.mutations, err)
+			}))
+		}
+
+		keep(tx.db.rolledBack(nil, tx.mutations, err))
+		return hookErr
+	}
+
+	for _, fn := range tx.committed {
+		fn()
+	}
+
+	keep(tx.db.emit(tx.events))
+
+	for _, hook := range tx.commitHooks {
+		keep(tx.db.callHook(nil, func() {
+			hook(tx.mutations)
+		}))
+	}
+
+	keep(tx.db.committed(nil, tx.mutations))
+	return hookErr
+}
+
+// ViewTx executes function `fn` within a read-only Tx, so that the operations on its Buckets see a single consistent view
+func (db *DB) ViewTx(fn func(*Tx) error) error {
+	return db.view(nil, func(boltTx *bolt.Tx) error {
+		return fn(&Tx{Tx: boltTx, db: db})
+	})
+}
+
+// InsertIntoBuckets puts the given Items in the bolt.Buckets with the given "/" separated names in a single transaction,
+// so that either all of them or none are written
+func (db *DB) InsertIntoBuckets(items map[string][]Item) error {
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return db.UpdateTx(func(tx *Tx) error {
+		for _, name := range names {
+			err := tx.BucketString(name).InsertAll(items[name])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Bucket returns the Bucket with the given "/" separated name bound to this Tx
+func (tx *Tx) Bucket(name []byte) *Bucket {
+	return tx.bind(tx.db.Bucket(name))
+}
+
+// BucketString is a convenience wrapper over Bucket for string name
+func (tx *Tx) BucketString(name string) *Bucket {
+	return tx.bind(tx.db.BucketString(name))
+}
+
+// BucketAt returns the Bucket with the given path segments bound to this Tx, see DB.BucketAt
+func (tx *Tx) BucketAt(path ...[]byte) *Bucket {
+	return tx.bind(tx.db.BucketAt(path...))
+}
+
+// UpdateBucket executes function `fn` with a copy of this Bucket bound to a read-write Tx, see DB.UpdateTx,
+// so that the operations on it and the Buckets reached from it through Child are applied atomically.
+// If this Bucket is already bound to a Tx, function `fn` runs within that Tx instead.
+func (b *Bucket) UpdateBucket(fn func(*Bucket) error) error {
+	if b.tx != nil {
+		return b.write(func(*bolt.Tx) error {
+			return fn(b)
+		})
+	}
+
+	return b.DB.UpdateTx(func(tx *Tx) error {
+		return fn(tx.Bind(b))
+	})
+}
+
+// ViewBucket executes function `fn` with a copy of this Bucket bound to a read-only Tx, see DB.ViewTx.
+// If this Bucket is already bound to a Tx, function `fn` runs within that Tx instead.
+func (b *Bucket) ViewBucket(fn func(*Bucket) error) error {
+	if b.tx != nil {
+		return b.read(func(*bolt.Tx) error {
+			return fn(b)
+		})
+	}
+
+	return b.DB.ViewTx(func(tx *Tx) error {
+		return fn(tx.Bind(b))
+	})
+}
+
+// Bind returns a copy of Bucket `b` bound to this Tx
+func (tx *Tx) Bind(b *Bucket) *Bucket {
+	bound := *b
+	return tx.bind(&bound)
+}
+
+func (tx *Tx) bind(b *Bucket) *Bucket {
+	b.tx = tx
+	return b
+}
+
+// read executes function `fn` within the Tx this Bucket is bound to, or a new read-only bolt.Tx
+func (b *Bucket) read(fn func(*bolt.Tx) error) error {
+	if err := b.checkReserved(); err != nil {
+		return err
+	}
+
+	if b.tx == nil {
+		return b.DB.view(b.Name, fn)
+	}
+
+	if b.tx.Tx.DB() == nil {
+		return bolt.ErrTxClosed
+	}
+
+	return fn(b.tx.Tx)
+}
+
+// write executes function `fn` within the Tx this Bucket is bound to, or a new read-write bolt.Tx
+func (b *Bucket) write(fn func(*bolt.Tx) error) error {
+	if err := b.checkReserved(); err != nil {
+		return err
+	}
+
+	if b.tx == nil {
+		return b.DB.update(b.Name, fn)
+	}
+
+	if b.tx.Tx.DB() == nil {
+		return bolt.ErrTxClosed
+	}
+
+	if !b.tx.Writable() {
+		return bolt.ErrTxNotWritable
+	}
+
+	if err := b.DB.checkTimeout(b.Name, b.tx.start, b.tx.timeout); err != nil {
+		return err
+	}
+
+	return fn(b.tx.Tx)
+}
+
+// writeBatched executes function `fn` within the Tx this Bucket is bound to, or a read-write bolt.Tx shared by a batch
+func (b *Bucket) writeBatched(fn func(*bolt.Tx) error) error {
+	if b.tx != nil {
+		return b.write(fn)
+	}
+
+	if err := b.checkReserved(); err != nil {
+		return err
+	}
+
+	return b.DB.batch(b.Name, fn)
+}
+
+// afterCommit calls function `fn` once the Tx this Bucket is bound to has been committed, or right away if it is not bound
+func (b *Bucket) afterCommit(fn func()) {
+	if b.tx == nil {
+		fn()
+		return
+	}
+
+	b.tx.committed = append(b.tx.committed, fn)
+}
@@ -0,0 +1,360 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestUpdateTx(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Inserting in data and index buckets in one transaction")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		err := tx.BucketString("Data").InsertString("user1", "Alice")
+		if err != nil {
+			return err
+		}
+
+		err = tx.BucketString("Index/Name").InsertString("Alice", "user1")
+		if err != nil {
+			return err
+		}
+
+		value, err := tx.BucketString("Data").GetString("user1")
+		if err != nil {
+			return err
+		}
+
+		if value != "Alice" {
+			t.Errorf("Value %s read within the transaction does not match the inserted value", value)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update in transaction. Error: %s", err.Error())
+	}
+
+	value, err := db.BucketString("Index/Name").GetString("Alice")
+	if err != nil {
+		t.Errorf("Unable to retrieve value. Error: %s", err.Error())
+	}
+
+	if value != "user1" {
+		t.Errorf("Committed value %s does not match the inserted value", value)
+	}
+
+	errFailed := errors.New("failed")
+
+	t.Log("Rolling back a transaction")
+	err = db.UpdateTx(func(tx *mbuckets.Tx) error {
+		err := tx.BucketString("Data").InsertString("user2", "Bob")
+		if err != nil {
+			return err
+		}
+
+		err = tx.BucketString("Rolled/Back").InsertString("key", "value")
+		if err != nil {
+			return err
+		}
+
+		return errFailed
+	})
+
+	if err != errFailed {
+		t.Errorf("Transaction did not return the error of the function. Error: %v", err)
+	}
+
+	exists, err := db.BucketString("Data").ExistsString("user2")
+	if err != nil {
+		t.Errorf("Unable to check key. Error: %s", err.Error())
+	}
+
+	if exists {
+		t.Error("Key inserted in a rolled back transaction exists")
+	}
+
+	exists, err = db.BucketExists([]byte("Rolled"))
+	if err != nil {
+		t.Errorf("Unable to check bucket. Error: %s", err.Error())
+	}
+
+	if exists {
+		t.Error("Bucket created in a rolled back transaction exists")
+	}
+}
+
+func TestViewTx(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Inserting key/value pair")
+	err = db.BucketString("Data").InsertString("key1", "value1")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	var bound *mbuckets.Bucket
+
+	t.Log("Reading and writing in a read-only transaction")
+	err = db.ViewTx(func(tx *mbuckets.Tx) error {
+		bound = tx.Bind(db.BucketString("Data"))
+
+		value, err := bound.GetString("key1")
+		if err != nil {
+			return err
+		}
+
+		if value != "value1" {
+			t.Errorf("Value %s does not match the inserted value", value)
+		}
+
+		err = bound.InsertString("key2", "value2")
+		if err != bolt.ErrTxNotWritable {
+			t.Errorf("Insert in a read-only transaction did not fail with ErrTxNotWritable. Error: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Unable to view in transaction. Error: %s", err.Error())
+	}
+
+	t.Log("Using a Bucket after its transaction has closed")
+	_, err = bound.GetString("key1")
+	if err != bolt.ErrTxClosed {
+		t.Errorf("Get after the transaction did not fail with ErrTxClosed. Error: %v", err)
+	}
+}
+
+func TestInsertIntoBuckets(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Inserting items in data and index buckets")
+	err = db.InsertIntoBuckets(map[string][]mbuckets.Item{
+		"Data":       {{Key: []byte("user1"), Value: []byte("Alice")}, {Key: []byte("user2"), Value: []byte("Bob")}},
+		"Index/Name": {{Key: []byte("Alice"), Value: []byte("user1")}, {Key: []byte("Bob"), Value: []byte("user2")}},
+	})
+
+	if err != nil {
+		t.Errorf("Unable to insert items in buckets. Error: %s", err.Error())
+	}
+
+	for name, key := range map[string]string{"Data": "user2", "Index/Name": "Bob"} {
+		exists, err := db.BucketString(name).ExistsString(key)
+		if err != nil {
+			t.Errorf("Unable to check key. Error: %s", err.Error())
+		}
+
+		if !exists {
+			t.Errorf("Key %s was not inserted in bucket %s", key, name)
+		}
+	}
+
+	t.Log("Making bucket Index/Name write-once")
+	err = db.BucketString("Index/Name").SetWriteOnce()
+	if err != nil {
+		t.Errorf("Unable to make bucket write-once. Error: %s", err.Error())
+	}
+
+	t.Log("Inserting items that fail in one of the buckets")
+	err = db.InsertIntoBuckets(map[string][]mbuckets.Item{
+		"Data":       {{Key: []byte("user3"), Value: []byte("Alice")}},
+		"Index/Name": {{Key: []byte("Alice"), Value: []byte("user3")}},
+	})
+
+	if !errors.Is(err, mbuckets.ErrWriteOnce) {
+		t.Errorf("Insert did not fail with ErrWriteOnce. Error: %v", err)
+	}
+
+	exists, err := db.BucketString("Data").ExistsString("user3")
+	if err != nil {
+		t.Errorf("Unable to check key. Error: %s", err.Error())
+	}
+
+	if exists {
+		t.Error("Key was inserted although the transaction failed")
+	}
+}
+
+func TestBegin(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	var committed []mbuckets.Mutation
+	db.OnCommit(func(mutations []mbuckets.Mutation) {
+		committed = mutations
+	})
+
+	t.Log("Beginning a read-write transaction")
+	tx, err := db.Begin(true)
+	if err != nil {
+		t.Fatalf("Unable to begin transaction. Error: %s", err.Error())
+	}
+
+	err = tx.BucketString("Users/Active").InsertString("user1", "Alice")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	t.Log("Committing the transaction")
+	err = tx.Commit()
+	if err != nil {
+		t.Errorf("Unable to commit transaction. Error: %s", err.Error())
+	}
+
+	if len(committed) != 1 {
+		t.Errorf("Committed mutations %v do not match the transaction", committed)
+	}
+
+	err = tx.Rollback()
+	if err != bolt.ErrTxClosed {
+		t.Errorf("Rollback after commit did not fail with ErrTxClosed. Error: %v", err)
+	}
+
+	t.Log("Beginning a read-write transaction to roll back")
+	tx, err = db.Begin(true)
+	if err != nil {
+		t.Fatalf("Unable to begin transaction. Error: %s", err.Error())
+	}
+
+	err = tx.BucketString("Users/Active").InsertString("user2", "Bob")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		t.Errorf("Unable to roll back transaction. Error: %s", err.Error())
+	}
+
+	t.Log("Beginning a read-only transaction")
+	tx, err = db.Begin(false)
+	if err != nil {
+		t.Fatalf("Unable to begin transaction. Error: %s", err.Error())
+	}
+	defer tx.Rollback()
+
+	keys, err := tx.BucketString("Users/Active").GetAllKeys()
+	if err != nil {
+		t.Errorf("Unable to retrieve keys. Error: %s", err.Error())
+	}
+
+	if len(keys) != 1 || string(keys[0]) != "user1" {
+		t.Errorf("Keys %q do not match the committed transaction", keys)
+	}
+
+	err = tx.Commit()
+	if err != bolt.ErrTxNotWritable {
+		t.Errorf("Commit of a read-only transaction did not fail with ErrTxNotWritable. Error: %v", err)
+	}
+}
+
+func TestUpdateBucket(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Users")
+
+	t.Log("Inserting into a bucket and its child in a failing transaction")
+	err = bucket.UpdateBucket(func(b *mbuckets.Bucket) error {
+		err := b.InsertString("alice", "admin")
+		if err != nil {
+			return err
+		}
+
+		err = b.ChildString("Profiles").InsertString("alice", "Alice")
+		if err != nil {
+			return err
+		}
+
+		return errors.New("Abort")
+	})
+
+	if err == nil || err.Error() != "Abort" {
+		t.Errorf("Update did not fail with the returned error. Error: %v", err)
+	}
+
+	exists, err := bucket.BucketExists()
+	if err != nil || exists {
+		t.Errorf("Bucket exists after the failed transaction. Error: %v", err)
+	}
+
+	t.Log("Inserting into a bucket and its child in a transaction")
+	err = bucket.UpdateBucket(func(b *mbuckets.Bucket) error {
+		err := b.InsertAllString(map[string]string{"alice": "admin", "bob": "user"})
+		if err != nil {
+			return err
+		}
+
+		return b.ChildString("Profiles").InsertString("alice", "Alice")
+	})
+
+	if err != nil {
+		t.Errorf("Unable to update in transaction. Error: %s", err.Error())
+	}
+
+	t.Log("Reading a bucket and its child in a transaction")
+	err = bucket.ViewBucket(func(b *mbuckets.Bucket) error {
+		items, err := b.GetPrefixString("a")
+		if err != nil {
+			return err
+		}
+
+		if len(items) != 1 || items["alice"] != "admin" {
+			t.Errorf("Items %v do not match the inserted items", items)
+		}
+
+		name, err := b.ChildString("Profiles").GetString("alice")
+		if err != nil {
+			return err
+		}
+
+		if name != "Alice" {
+			t.Errorf("Value %s does not match the inserted value", name)
+		}
+
+		err = b.InsertString("carol", "user")
+		if err != bolt.ErrTxNotWritable {
+			t.Errorf("Insert in a read-only transaction did not fail with ErrTxNotWritable. Error: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("Unable to view in transaction. Error: %s", err.Error())
+	}
+}
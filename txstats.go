@@ -0,0 +1,116 @@
+package mbuckets
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// TxStat describes a transaction run through a DB
+type TxStat struct {
+	// Complete hierarchial name of the Bucket the transaction was started for, nil for DB level transactions
+	Bucket []byte
+
+	// Whether the transaction was read-write
+	Writable bool
+
+	// Time taken by the transaction, including its commit
+	Duration time.Duration
+
+	// Number of keys put and deleted through Buckets, and the bytes of the keys and values put
+	Puts         int
+	Deletes      int
+	BytesWritten int
+
+	// Error returned by the transaction, nil on success
+	Err error
+}
+
+// TrackTxStats sets whether a TxStat is recorded for every transaction run through this DB, see LastTxStats and OnTxStats.
+// Transactions shared by DB.Batch and Tx handles from DB.Begin are not recorded.
+func (db *DB) TrackTxStats(enabled bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.txStats = nil
+	if enabled {
+		db.txStats = make(map[*bolt.Tx]*TxStat)
+	}
+}
+
+// LastTxStats returns the TxStat of the last transaction finished through this DB,
+// and reports false if none was recorded since tracking was enabled
+func (db *DB) LastTxStats() (TxStat, bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.lastTxStat == nil {
+		return TxStat{}, false
+	}
+
+	return *db.lastTxStat, true
+}
+
+// OnTxStats sets function `fn` to be called with the TxStat of every transaction finished through this DB while tracking is enabled.
+// Passing nil stops reporting. Function `fn` is called on the goroutine that ran the transaction.
+func (db *DB) OnTxStats(fn func(TxStat)) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.txStatsHook = fn
+}
+
+// recordTx wraps transaction function `fn` to record its TxStat if tracking is enabled.
+// The returned finish function must be called with the error of the transaction once it has finished,
+// and returns the error of the hook set by OnTxStats, see DB.callHook.
+func (db *DB) recordTx(name []byte, writable bool, fn func(*bolt.Tx) error) (func(*bolt.Tx) error, func(error) error) {
+	db.mu.RLock()
+	enabled := db.txStats != nil
+	db.mu.RUnlock()
+
+	if !enabled {
+		return fn, func(error) error { return nil }
+	}
+
+	start := db.now()
+	var stat *TxStat
+	var current *bolt.Tx
+
+	wrapped := func(tx *bolt.Tx) error {
+		db.mu.Lock()
+		if db.txStats != nil {
+			delete(db.txStats, current)
+			stat = &TxStat{Bucket: name, Writable: writable}
+			current = tx
+			db.txStats[tx] = stat
+		}
+		db.mu.Unlock()
+
+		return fn(tx)
+	}
+
+	finish := func(err error) error {
+		if stat == nil {
+			return nil
+		}
+
+		stat.Duration = db.now().Sub(start)
+		stat.Err = err
+
+		db.mu.Lock()
+		delete(db.txStats, current)
+		db.lastTxStat = stat
+		hook := db.txStatsHook
+		db.mu.Unlock()
+
+		if hook == nil {
+			return nil
+		}
+
+		return db.callHook(name, func() {
+			hook(*stat)
+		})
+	}
+
+	return wrapped, finish
+}
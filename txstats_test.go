@@ -0,0 +1,78 @@
+package mbuckets_test
+
+import (
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestTxStats(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1")
+
+	t.Log("Inserting a key/value pair before tracking")
+	err = bucket.InsertString("key0", "value0")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	if _, ok := db.LastTxStats(); ok {
+		t.Error("Transaction stats recorded before tracking was enabled")
+	}
+
+	var reported []mbuckets.TxStat
+	db.OnTxStats(func(stat mbuckets.TxStat) {
+		reported = append(reported, stat)
+	})
+
+	t.Log("Enabling transaction stats")
+	db.TrackTxStats(true)
+
+	t.Log("Inserting and deleting key/value pairs")
+	err = bucket.InsertAllString(map[string]string{"key1": "value1", "key2": "value2"})
+	if err != nil {
+		t.Errorf("Unable to insert key/value pairs. Error: %s", err.Error())
+	}
+
+	stat, ok := db.LastTxStats()
+	if !ok {
+		t.Fatal("Transaction stats not recorded")
+	}
+
+	t.Logf("Transaction stats: %+v", stat)
+	if !stat.Writable || stat.Puts != 2 || stat.Deletes != 0 || stat.BytesWritten != 20 || string(stat.Bucket) != "Bucket1" {
+		t.Errorf("Transaction stats %+v do not match the insert", stat)
+	}
+
+	_, err = bucket.DeletePrefix([]byte("key"))
+	if err != nil {
+		t.Errorf("Unable to delete prefix. Error: %s", err.Error())
+	}
+
+	stat, _ = db.LastTxStats()
+	if stat.Deletes != 3 || stat.Puts != 0 {
+		t.Errorf("Transaction stats %+v do not match the delete", stat)
+	}
+
+	t.Log("Reading a missing key")
+	_, err = bucket.GetString("missing")
+	if err == nil {
+		t.Error("Reading a missing key did not fail")
+	}
+
+	stat, _ = db.LastTxStats()
+	if stat.Writable || stat.Err == nil {
+		t.Errorf("Transaction stats %+v do not match the failed read", stat)
+	}
+
+	if len(reported) != 3 {
+		t.Errorf("Number of reported transaction stats %d does not match the transactions", len(reported))
+	}
+}
@@ -0,0 +1,164 @@
+package mbuckets
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Metrics checked against Thresholds and CompactionPolicy, reported in Alerts
+const (
+	MetricFileSize      = "file_size"
+	MetricFreePageRatio = "free_page_ratio"
+	MetricBucketCount   = "bucket_count"
+	MetricFreeBytes     = "free_bytes"
+	MetricDeletedKeys   = "deleted_keys"
+)
+
+// Actions suggested in Alerts
+const (
+	// Copy the live data into a new file to release free pages, see bolt.Tx.WriteTo
+	ActionCompact = "compact"
+
+	// Delete data or buckets that are no longer needed
+	ActionPrune = "prune"
+)
+
+// Thresholds configures the limits checked by CheckThresholds and Watch, a zero value disables a check
+type Thresholds struct {
+	// Maximum size of the database file in bytes
+	FileSize int64
+
+	// Maximum ratio of free pages to all pages in the database, between 0 and 1
+	FreePageRatio float64
+
+	// Maximum number of buckets in the database, including nested buckets
+	BucketCount int
+}
+
+// Alert describes a threshold exceeded by a DB
+type Alert struct {
+	// One of the Metric constants
+	Metric string
+
+	// The measured value of the metric
+	Value float64
+
+	// The threshold exceeded by the value
+	Threshold float64
+
+	// One of the Action constants
+	Action string
+}
+
+// CheckThresholds measures this DB and returns an Alert for every threshold exceeded
+func (db *DB) CheckThresholds(thresholds Thresholds) ([]Alert, error) {
+	if db.IsClosed() {
+		return nil, ErrDBClosed
+	}
+
+	var alerts []Alert
+
+	if thresholds.FileSize > 0 {
+		info, err := os.Stat(db.Path())
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Size() > thresholds.FileSize {
+			alerts = append(alerts, Alert{MetricFileSize, float64(info.Size()), float64(thresholds.FileSize), ActionCompact})
+		}
+	}
+
+	if thresholds.FreePageRatio > 0 {
+		ratio, err := db.freePageRatio()
+		if err != nil {
+			return nil, err
+		}
+
+		if ratio > thresholds.FreePageRatio {
+			alerts = append(alerts, Alert{MetricFreePageRatio, ratio, thresholds.FreePageRatio, ActionCompact})
+		}
+	}
+
+	if thresholds.BucketCount > 0 {
+		names, err := db.GetAllBucketNames()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(names) > thresholds.BucketCount {
+			alerts = append(alerts, Alert{MetricBucketCount, float64(len(names)), float64(thresholds.BucketCount), ActionPrune})
+		}
+	}
+
+	return alerts, nil
+}
+
+// freePageRatio returns the ratio of free and pending pages to all pages in this DB
+func (db *DB) freePageRatio() (float64, error) {
+	var pages int64
+	err := db.View(func(tx *bolt.Tx) error {
+		pages = tx.Size() / int64(db.Info().PageSize)
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	stats := db.Stats()
+	return float64(stats.FreePageN+stats.PendingPageN) / float64(pages), nil
+}
+
+// Watch checks the given thresholds every interval in a new goroutine, and calls function `fn` with every Alert.
+// Watching stops when the returned function is called or this DB is closed.
+// An error is returned if the interval is not positive.
+func (db *DB) Watch(thresholds Thresholds, interval time.Duration, fn func(Alert)) (stop func(), err error) {
+	return db.watch(interval, func() error {
+		alerts, err := db.CheckThresholds(thresholds)
+		for _, alert := range alerts {
+			db.callHook(nil, func() {
+				fn(alert)
+			})
+		}
+		return err
+	})
+}
+
+// watch calls function `check` every interval in a new goroutine,
+// until the returned function is called or function `check` returns ErrDBClosed
+func (db *DB) watch(interval time.Duration, check func() error) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("Invalid watch interval: %s", interval)
+	}
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			if check() == ErrDBClosed {
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}, nil
+}
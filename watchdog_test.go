@@ -0,0 +1,71 @@
+package mbuckets_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestCheckThresholds(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	for i := 0; i < 5; i++ {
+		err = db.BucketString(fmt.Sprintf("Bucket%d", i)).InsertString("key", "value")
+		if err != nil {
+			t.Errorf("Unable to insert key/value in bucket. Error: %s", err.Error())
+		}
+	}
+
+	t.Log("Checking thresholds that are not exceeded")
+	alerts, err := db.CheckThresholds(mbuckets.Thresholds{FileSize: 1 << 30, BucketCount: 5})
+	if err != nil {
+		t.Errorf("Unable to check thresholds. Error: %s", err.Error())
+	}
+
+	if len(alerts) != 0 {
+		t.Errorf("Unexpected alerts %v for thresholds that are not exceeded", alerts)
+	}
+
+	t.Log("Checking thresholds that are exceeded")
+	alerts, err = db.CheckThresholds(mbuckets.Thresholds{FileSize: 1, BucketCount: 4})
+	if err != nil {
+		t.Errorf("Unable to check thresholds. Error: %s", err.Error())
+	}
+
+	if len(alerts) != 2 || alerts[0].Metric != mbuckets.MetricFileSize || alerts[1].Action != mbuckets.ActionPrune {
+		t.Errorf("Alerts %v do not match the exceeded thresholds", alerts)
+	}
+
+	t.Log("Watching thresholds that are exceeded")
+	received := make(chan mbuckets.Alert, 10)
+	stop, err := db.Watch(mbuckets.Thresholds{BucketCount: 1}, 10*time.Millisecond, func(alert mbuckets.Alert) {
+		received <- alert
+	})
+	if err != nil {
+		t.Fatalf("Unable to watch thresholds. Error: %s", err.Error())
+	}
+	defer stop()
+
+	select {
+	case alert := <-received:
+		if alert.Metric != mbuckets.MetricBucketCount || alert.Value != 5 {
+			t.Errorf("Alert %v does not match the exceeded threshold", alert)
+		}
+	case <-time.After(time.Second):
+		t.Error("No alert received while watching an exceeded threshold")
+	}
+
+	t.Log("Watching with an invalid interval")
+	_, err = db.Watch(mbuckets.Thresholds{BucketCount: 1}, 0, func(mbuckets.Alert) {})
+	if err == nil {
+		t.Error("Watching with a zero interval did not fail")
+	}
+}
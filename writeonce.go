@@ -0,0 +1,98 @@
+package mbuckets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+// ErrWriteOnce is returned, wrapped with the key and bucket name, when overwriting or deleting a key in a write-once bolt.Bucket
+var ErrWriteOnce = errors.New("Write-once bucket")
+
+// Name of the meta bucket holding the paths of write-once bolt.Buckets
+var writeOnceBucketName = []byte("write_once")
+
+// SetWriteOnce makes the bolt.Bucket specified by this Bucket write-once, creating it if needed.
+// New keys can still be put in a write-once bolt.Bucket, but existing keys can never be overwritten or deleted,
+// and neither the bolt.Bucket nor any bolt.Bucket above it can be deleted. Such attempts fail with an error wrapping ErrWriteOnce.
+//
+// The mode is persisted and can not be turned off. It is enforced by the methods of Bucket, including ImportItems,
+// but not for writes made by functions passed to Update or directly through the embedded bolt.DB.
+func (b *Bucket) SetWriteOnce() error {
+	key := []byte(pathKey(b.segments()))
+
+	return b.track(OpSetWriteOnce, nil, func() error {
+		return b.updatePath(true, func(bucket *bolt.Bucket, tx *bolt.Tx) error {
+			writeOnce, err := metaBucket(tx, writeOnceBucketName)
+			if err != nil {
+				return err
+			}
+
+			return writeOnce.Put(key, []byte{1})
+		})
+	})
+}
+
+// IsWriteOnce reports whether the bolt.Bucket specified by this Bucket is write-once, see SetWriteOnce
+func (b *Bucket) IsWriteOnce() (writeOnce bool, err error) {
+	err = b.read(func(tx *bolt.Tx) error {
+		writeOnce = isWriteOnce(tx, b.segments())
+		return nil
+	})
+
+	return writeOnce, err
+}
+
+// isWriteOnce reports whether the bolt.Bucket with the given path is write-once
+func isWriteOnce(tx *bolt.Tx, buckets [][]byte) bool {
+	meta := tx.Bucket(metaBucketName)
+	if meta == nil {
+		return false
+	}
+
+	writeOnce := meta.Bucket(writeOnceBucketName)
+	return writeOnce != nil && writeOnce.Get([]byte(pathKey(buckets))) != nil
+}
+
+// containsWriteOnce reports whether the bolt.Bucket with the given path, or any bolt.Bucket under it, is write-once
+func containsWriteOnce(tx *bolt.Tx, buckets [][]byte) bool {
+	meta := tx.Bucket(metaBucketName)
+	if meta == nil {
+		return false
+	}
+
+	writeOnce := meta.Bucket(writeOnceBucketName)
+	if writeOnce == nil {
+		return false
+	}
+
+	prefix := []byte(pathKey(buckets))
+	k, _ := writeOnce.Cursor().Seek(prefix)
+	return k != nil && bytes.HasPrefix(k, prefix)
+}
+
+// delete removes the given key from bolt.Bucket `bucket` specified by this Bucket, unless it is write-once
+func (b *Bucket) delete(bucket *bolt.Bucket, key []byte) error {
+	exists := bucket.Get(key) != nil
+	if exists && isWriteOnce(bucket.Tx(), b.segments()) {
+		return writeOnceError(b.Name, key)
+	}
+
+	err := bucket.Delete(key)
+	if err == nil && exists {
+		b.DB.countDeletes(bucket.Tx(), 1)
+		b.DB.recordKeyChange(bucket.Tx(), b.segments(), key, false)
+	}
+
+	return err
+}
+
+func writeOnceError(name, key []byte) error {
+	if key == nil {
+		return fmt.Errorf("%w: %s", ErrWriteOnce, name)
+	}
+
+	return fmt.Errorf("%w: key %s in %s", ErrWriteOnce, key, name)
+}
@@ -0,0 +1,144 @@
+package mbuckets_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/abhigupta912/mbuckets"
+)
+
+func TestWriteOnce(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	bucket := db.BucketString("Bucket1/Events")
+
+	t.Logf("Making bucket write-once: %s", bucket.Name)
+	err = bucket.SetWriteOnce()
+	if err != nil {
+		t.Errorf("Unable to make bucket write-once. Error: %s", err.Error())
+	}
+
+	writeOnce, err := bucket.IsWriteOnce()
+	if err != nil || !writeOnce {
+		t.Errorf("Bucket is not reported as write-once. Error: %v", err)
+	}
+
+	t.Log("Inserting new keys")
+	err = bucket.InsertAllString(map[string]string{"key1": "value1", "key2": "value2"})
+	if err != nil {
+		t.Errorf("Unable to insert new keys in write-once bucket. Error: %s", err.Error())
+	}
+
+	_, err = bucket.IncrementString("counter", 1)
+	if err != nil {
+		t.Errorf("Unable to create counter in write-once bucket. Error: %s", err.Error())
+	}
+
+	failures := map[string]func() error{
+		"overwrite":     func() error { return bucket.InsertString("key1", "changed") },
+		"delete":        func() error { return bucket.DeleteString("key1") },
+		"take":          func() error { _, err := bucket.TakeString("key1"); return err },
+		"delete prefix": func() error { _, err := bucket.DeletePrefix([]byte("key")); return err },
+		"clear":         func() error { return db.BucketString("Bucket1").ClearRecursive() },
+		"increment":     func() error { _, err := bucket.IncrementString("counter", 1); return err },
+		"rename":        func() error { return bucket.RenameKeyString("key1", "key3") },
+		"delete bucket": func() error { return bucket.DeleteBucket() },
+		"delete parent": func() error { return db.BucketString("Bucket1").DeleteBucket() },
+	}
+
+	for name, fn := range failures {
+		t.Logf("Attempting %s in write-once bucket", name)
+		if err := fn(); !errors.Is(err, mbuckets.ErrWriteOnce) {
+			t.Errorf("Attempt to %s in write-once bucket did not return ErrWriteOnce. Error: %v", name, err)
+		}
+	}
+
+	items, err := bucket.GetAllString()
+	if err != nil || len(items) != 3 || items["key1"] != "value1" {
+		t.Errorf("Key/value pairs %v of write-once bucket were changed. Error: %v", items, err)
+	}
+}
+
+func TestWriteOnceImport(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	t.Log("Exporting key1 with a new value")
+	err = db.BucketString("Source").InsertString("key1", "new")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	err = db.BucketString("Source").ExportItems(&buf, mbuckets.FormatNDJSON)
+	if err != nil {
+		t.Errorf("Unable to export items. Error: %s", err.Error())
+	}
+
+	t.Log("Importing over key1 in a write-once bucket")
+	ledger := db.BucketString("Ledger")
+	err = ledger.InsertString("key1", "orig")
+	if err != nil {
+		t.Errorf("Unable to insert key/value pair. Error: %s", err.Error())
+	}
+
+	err = ledger.SetWriteOnce()
+	if err != nil {
+		t.Errorf("Unable to set write-once. Error: %s", err.Error())
+	}
+
+	err = ledger.ImportItems(&buf, mbuckets.FormatNDJSON)
+	if !errors.Is(err, mbuckets.ErrWriteOnce) {
+		t.Errorf("Import over a write-once key did not fail with ErrWriteOnce. Error: %v", err)
+	}
+
+	value, err := ledger.GetString("key1")
+	if err != nil || value != "orig" {
+		t.Errorf("Value %s of the write-once key was overwritten. Error: %v", value, err)
+	}
+}
+
+func TestReservedBucket(t *testing.T) {
+	t.Log("Creating a new test db")
+	db, err := NewTestDB()
+	if err != nil {
+		t.Errorf("Unable to create the test db. Error: %s", err.Error())
+	}
+	defer db.Close()
+	t.Log("Successfully created a new test db")
+
+	err = db.BucketString("Ledger").SetWriteOnce()
+	if err != nil {
+		t.Errorf("Unable to set write-once. Error: %s", err.Error())
+	}
+
+	t.Log("Clearing the meta bucket")
+	for _, bucket := range []*mbuckets.Bucket{db.BucketString("__mbuckets__/write_once"), db.BucketAtString("__mbuckets__")} {
+		err = bucket.Clear()
+		if !errors.Is(err, mbuckets.ErrReservedBucket) {
+			t.Errorf("Clearing reserved bucket %s did not fail with ErrReservedBucket. Error: %v", bucket.Name, err)
+		}
+
+		_, err = bucket.GetAllKeys()
+		if !errors.Is(err, mbuckets.ErrReservedBucket) {
+			t.Errorf("Reading reserved bucket %s did not fail with ErrReservedBucket. Error: %v", bucket.Name, err)
+		}
+	}
+
+	writeOnce, err := db.BucketString("Ledger").IsWriteOnce()
+	if err != nil || !writeOnce {
+		t.Errorf("Write-once mark was removed. Error: %v", err)
+	}
+}
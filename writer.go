@@ -0,0 +1,108 @@
+package mbuckets
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+)
+
+// handle registers a DB opened while CheckSingleWriter is enabled
+type handle struct {
+	path     string
+	readOnly bool
+
+	// Stack trace of the goroutine that opened the DB
+	stack []byte
+}
+
+// Open handles keyed by absolute path, while CheckSingleWriter is enabled
+var handles struct {
+	sync.Mutex
+	enabled bool
+	open    map[string][]*handle
+}
+
+// HandleConflictError is returned by Open and OpenWith with CheckSingleWriter enabled, when the database file
+// is already open through another DB in this process and one of the two is not read-only.
+// Without the check, bolt would wait for its file lock until the Timeout of its options, or forever.
+type HandleConflictError struct {
+	// Absolute path of the database file
+	Path string
+
+	// Stack trace of the goroutine that opened the existing DB
+	ExistingStack []byte
+
+	// Stack trace of the goroutine opening the conflicting DB
+	Stack []byte
+}
+
+func (e *HandleConflictError) Error() string {
+	return fmt.Sprintf("Database %s is already open in this process, opened at:\n%s", e.Path, e.ExistingStack)
+}
+
+// CheckSingleWriter sets whether Open and OpenWith fail with a *HandleConflictError when a database file
+// is opened through a second DB in the same process while the first is still open, unless both are read-only.
+// Only DBs opened while the check is enabled are taken into account.
+//
+// The check is meant to diagnose deadlocks against the file lock of bolt during development and tests.
+func CheckSingleWriter(enabled bool) {
+	handles.Lock()
+	defer handles.Unlock()
+
+	handles.enabled = enabled
+}
+
+// openHandle registers the opening of the database file at the given path if CheckSingleWriter is enabled,
+// and returns nil otherwise
+func openHandle(path string, readOnly bool) (*handle, error) {
+	handles.Lock()
+	defer handles.Unlock()
+
+	if !handles.enabled {
+		return nil, nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range handles.open[absPath] {
+		if !readOnly || !existing.readOnly {
+			return nil, &HandleConflictError{absPath, existing.stack, debug.Stack()}
+		}
+	}
+
+	if handles.open == nil {
+		handles.open = make(map[string][]*handle)
+	}
+
+	h := &handle{absPath, readOnly, debug.Stack()}
+	handles.open[absPath] = append(handles.open[absPath], h)
+	return h, nil
+}
+
+// closeHandle removes the given registration, if any
+func closeHandle(h *handle) {
+	if h == nil {
+		return
+	}
+
+	handles.Lock()
+	defer handles.Unlock()
+
+	open := handles.open[h.path]
+	for idx, existing := range open {
+		if existing == h {
+			open = append(open[:idx], open[idx+1:]...)
+			break
+		}
+	}
+
+	if len(open) == 0 {
+		delete(handles.open, h.path)
+	} else {
+		handles.open[h.path] = open
+	}
+}
@@ -0,0 +1,56 @@
+package mbuckets_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/abhigupta912/mbuckets"
+	"github.com/boltdb/bolt"
+)
+
+func TestCheckSingleWriter(t *testing.T) {
+	mbuckets.CheckSingleWriter(true)
+	defer mbuckets.CheckSingleWriter(false)
+
+	fileName := tempFile()
+	defer os.Remove(fileName)
+
+	t.Log("Opening the test db")
+	db, err := mbuckets.Open(fileName)
+	if err != nil {
+		t.Fatalf("Unable to open the test db. Error: %s", err.Error())
+	}
+
+	t.Log("Opening the test db again")
+	start := time.Now()
+	_, err = mbuckets.Open(fileName)
+
+	var conflict *mbuckets.HandleConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Opening the test db twice did not fail with a HandleConflictError. Error: %v", err)
+	}
+
+	t.Logf("Conflict reported after %s for path: %s", time.Since(start), conflict.Path)
+	if len(conflict.ExistingStack) == 0 || len(conflict.Stack) == 0 {
+		t.Error("Conflict does not hold the stack traces of both openings")
+	}
+
+	t.Log("Closing the test db and opening it twice read-only")
+	db.Close()
+
+	readOnly := &bolt.Options{Timeout: time.Second, ReadOnly: true}
+
+	first, err := mbuckets.OpenWith(fileName, 0600, readOnly)
+	if err != nil {
+		t.Fatalf("Unable to open the test db read-only. Error: %s", err.Error())
+	}
+	defer first.Close()
+
+	second, err := mbuckets.OpenWith(fileName, 0600, readOnly)
+	if err != nil {
+		t.Fatalf("Unable to open the test db read-only twice. Error: %s", err.Error())
+	}
+	defer second.Close()
+}